@@ -2,16 +2,21 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"backend/configs"
 	"backend/internal/repositories"
 	"backend/internal/services"
+	"backend/internal/utils"
+	"backend/pkg/contracts"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/sirupsen/logrus"
@@ -45,11 +50,23 @@ func init() {
 }
 
 func main() {
+	recomputeFrom := flag.String("recompute-from", "", "пересчитать активность аккаунтов начиная с момента (RFC3339) и завершиться")
+	recomputeTo := flag.String("recompute-to", "", "конец интервала пересчета активности (RFC3339, по умолчанию - текущее время)")
+	flag.Parse()
+
 	logrus.Info("🚀 Запуск анализатора активности блокчейна")
 
 	// Загружаем конфигурацию
 	cfg := configs.Load()
 
+	// Точность вывода сумм в уведомлениях, логах и экспорте
+	utils.SetAmountPrecision(cfg.Format.AmountPrecision)
+
+	// Базовый период агрегации активности
+	if !utils.SetActivityPeriod(cfg.Activity.Period) {
+		logrus.Warnf("ACTIVITY_PERIOD=%v не делит минуту нацело, используется %v", cfg.Activity.Period, utils.ActivityPeriod())
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -71,12 +88,29 @@ func main() {
 
 	logrus.Infof("📋 Используем ERC20 контракт: %s", contractAddr.Hex())
 
+	// По умолчанию используется встроенный ABI, внешний артефакт подключается явно
+	if cfg.Ethereum.ContractABIPath != "" {
+		contracts.SetArtifactPath(cfg.Ethereum.ContractABIPath)
+		logrus.Infof("📄 ABI контракта загружается из %s", cfg.Ethereum.ContractABIPath)
+	}
+
 	// Создаем и запускаем анализатор
 	analyzer, err := services.NewAnalyzer(cfg)
 	if err != nil {
 		logrus.Fatalf("Ошибка создания анализатора: %v", err)
 	}
 
+	// Режим пересчета: заново собираем активность за интервал из сохраненных данных и завершаемся
+	if *recomputeFrom != "" {
+		if err := recomputeActivities(ctx, analyzer, *recomputeFrom, *recomputeTo); err != nil {
+			logrus.Fatalf("Ошибка пересчета активности: %v", err)
+		}
+		if err := repositories.Close(); err != nil {
+			logrus.Errorf("Ошибка закрытия БД: %v", err)
+		}
+		return
+	}
+
 	if err := analyzer.Start(ctx); err != nil {
 		logrus.Fatalf("Ошибка запуска анализатора: %v", err)
 	}
@@ -85,6 +119,28 @@ func main() {
 	waitForShutdown(cancel, analyzer)
 }
 
+// recomputeActivities разбирает границы интервала и пересчитывает активность за него
+func recomputeActivities(ctx context.Context, analyzer *services.Analyzer, fromValue, toValue string) error {
+	from, err := time.Parse(time.RFC3339, fromValue)
+	if err != nil {
+		return fmt.Errorf("неверный формат -recompute-from: %v", err)
+	}
+
+	to := time.Now()
+	if toValue != "" {
+		if to, err = time.Parse(time.RFC3339, toValue); err != nil {
+			return fmt.Errorf("неверный формат -recompute-to: %v", err)
+		}
+	}
+
+	if !from.Before(to) {
+		return fmt.Errorf("начало интервала %v не раньше конца %v", from, to)
+	}
+
+	logrus.Infof("🔁 Пересчет активности аккаунтов за %v - %v", from, to)
+	return analyzer.RecomputeActivities(ctx, from, to)
+}
+
 func waitForShutdown(cancel context.CancelFunc, analyzer *services.Analyzer) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -15,6 +17,11 @@ type Config struct {
 	Ethereum    EthereumConfig
 	Server      ServerConfig
 	TelegramBot TelegramBotConfig
+	Listener    ListenerConfig
+	Relayer     RelayerConfig
+	Reconciler  ReconcilerConfig
+	Format      FormatConfig
+	Activity    ActivityConfig
 }
 
 type DatabaseConfig struct {
@@ -27,10 +34,16 @@ type DatabaseConfig struct {
 }
 
 type EthereumConfig struct {
-	RPCEndpoint     string
-	ChainID         int64
-	PrivateKey      string
-	ContractAddress string
+	RPCEndpoint      string
+	WSEndpoint       string
+	ChainID          int64
+	PrivateKey       string
+	ContractAddress  string
+	ContractABIPath  string // внешний артефакт Hardhat вместо встроенного ABI (опционально)
+	MulticallAddress string // адрес Multicall3 (по умолчанию канонический)
+	SignaturesFile   string // файл сигнатур методов для импорта в локальную базу (опционально)
+
+	TraceInternalTransfers bool // учитывать внутренние переводы ETH через debug_traceTransaction
 }
 
 type ServerConfig struct {
@@ -38,6 +51,30 @@ type ServerConfig struct {
 	Host string
 }
 
+type ListenerConfig struct {
+	Mode          string   // polling (по умолчанию) или subscription
+	EventsFile    string   // путь к NDJSON файлу для дублирования событий (опционально)
+	FromAddresses []string // фильтр событий по отправителю (пусто - все)
+	ToAddresses   []string // фильтр событий по получателю (пусто - все)
+}
+
+type RelayerConfig struct {
+	Addresses []string // адреса, внешние транзакции которых записываются в контракт (пусто - ретранслятор выключен)
+	BatchSize int      // количество ретрансляций за один цикл
+}
+
+type ReconcilerConfig struct {
+	DriftThreshold float64 // порог расхождения с контрактом в процентах для оповещения (0 - любое, отрицательный - по умолчанию)
+}
+
+type FormatConfig struct {
+	AmountPrecision int // знаков после запятой в выводимых суммах ETH и токенов (-1 - все знаки)
+}
+
+type ActivityConfig struct {
+	Period time.Duration // базовый период агрегации активности, должен делить минуту нацело
+}
+
 type TelegramBotConfig struct {
 	BotToken string
 	ChatID   string // теперь просто строка для одного ID
@@ -62,6 +99,22 @@ func Load() *Config {
 		logrus.Fatalf("Неверный формат ChainID: %v", err)
 	}
 
+	relayerBatchSize, _ := strconv.Atoi(os.Getenv("RELAYER_BATCH_SIZE"))
+	driftThreshold := -1.0 // не задан - порог по умолчанию
+	if value, err := strconv.ParseFloat(os.Getenv("RECONCILE_DRIFT_THRESHOLD"), 64); err == nil {
+		driftThreshold = value
+	}
+
+	amountPrecision := 6
+	if value, err := strconv.Atoi(os.Getenv("AMOUNT_PRECISION")); err == nil {
+		amountPrecision = value
+	}
+
+	activityPeriod := 15 * time.Second
+	if value, err := time.ParseDuration(os.Getenv("ACTIVITY_PERIOD")); err == nil {
+		activityPeriod = value
+	}
+
 	return &Config{
 		Database: DatabaseConfig{
 			Host:     os.Getenv("DB_HOST"),
@@ -72,10 +125,16 @@ func Load() *Config {
 			SSLMode:  os.Getenv("DB_SSL_MODE"),
 		},
 		Ethereum: EthereumConfig{
-			RPCEndpoint:     os.Getenv("ETH_RPC_ENDPOINT"),
-			ChainID:         chainID,
-			PrivateKey:      os.Getenv("ETH_PRIVATE_KEY"),
-			ContractAddress: os.Getenv("ETH_CONTRACT_ADDRESS"),
+			RPCEndpoint:      os.Getenv("ETH_RPC_ENDPOINT"),
+			WSEndpoint:       os.Getenv("ETH_WS_ENDPOINT"),
+			ChainID:          chainID,
+			PrivateKey:       os.Getenv("ETH_PRIVATE_KEY"),
+			ContractAddress:  os.Getenv("ETH_CONTRACT_ADDRESS"),
+			ContractABIPath:  os.Getenv("ETH_CONTRACT_ABI_PATH"),
+			MulticallAddress: os.Getenv("ETH_MULTICALL_ADDRESS"),
+			SignaturesFile:   os.Getenv("ETH_SIGNATURES_FILE"),
+
+			TraceInternalTransfers: os.Getenv("ETH_TRACE_INTERNAL_TRANSFERS") != "false",
 		},
 		Server: ServerConfig{
 			Port: os.Getenv("SERVER_PORT"),
@@ -85,5 +144,35 @@ func Load() *Config {
 			BotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
 			ChatID:   os.Getenv("TELEGRAM_CHAT_ID"), // один ID
 		},
+		Listener: ListenerConfig{
+			Mode:          os.Getenv("LISTENER_MODE"),
+			EventsFile:    os.Getenv("LISTENER_EVENTS_FILE"),
+			FromAddresses: splitList(os.Getenv("LISTENER_FROM_ADDRESSES")),
+			ToAddresses:   splitList(os.Getenv("LISTENER_TO_ADDRESSES")),
+		},
+		Relayer: RelayerConfig{
+			Addresses: splitList(os.Getenv("RELAYER_ADDRESSES")),
+			BatchSize: relayerBatchSize,
+		},
+		Reconciler: ReconcilerConfig{
+			DriftThreshold: driftThreshold,
+		},
+		Format: FormatConfig{
+			AmountPrecision: amountPrecision,
+		},
+		Activity: ActivityConfig{
+			Period: activityPeriod,
+		},
+	}
+}
+
+// splitList разбирает список значений, разделенных запятыми
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"backend/internal/utils"
 	"math/big"
 	"time"
 )
@@ -8,12 +9,24 @@ import (
 type AccountActivity struct {
 	ID               uint      `gorm:"primarykey"`
 	Address          string    `gorm:"uniqueIndex:idx_address_period;not null;type:char(42);check:address != '0x0000000000000000000000000000000000000000' AND address != ''" json:"address"`
-	Period           time.Time `gorm:"column:period;uniqueIndex:idx_address_period;not null"` // 15-секундные периоды
+	Period           time.Time `gorm:"column:period;uniqueIndex:idx_address_period;not null"` // начало базового периода (ACTIVITY_PERIOD)
 	TransactionCount uint32    `gorm:"default:0"`
-	VolumeETH        string    `gorm:"default:'0'"`
+	VolumeETH        BigInt    `gorm:"not null;default:0"`
 	TokenTransfers   uint32    `gorm:"default:0"`
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
+	TokenVolume      BigInt    `gorm:"not null;default:0"` // объем исходящих ERC20 трансферов в минимальных единицах
+	// Входящая активность
+	ReceivedCount          uint32 `gorm:"not null;default:0"` // входящие ETH транзакции
+	VolumeETHReceived      BigInt `gorm:"not null;default:0"`
+	TokenTransfersReceived uint32 `gorm:"not null;default:0"`
+	TokenVolumeReceived    BigInt `gorm:"not null;default:0"`
+	Counterparties         uint32 `gorm:"not null;default:0"` // различные адреса, с которыми аккаунт обменивался ETH или токенами
+	// Комиссии по всем отправленным транзакциям, включая вызовы контрактов
+	FeesPaid    BigInt `gorm:"not null;default:0"`
+	GasUsed     uint64 `gorm:"not null;default:0"`
+	GasPriceP50 BigInt `gorm:"not null;default:0"` // медиана цены газа
+	GasPriceP90 BigInt `gorm:"not null;default:0"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // Добавляем составной уникальный индекс на (address, period)
@@ -21,25 +34,109 @@ func (AccountActivity) TableName() string {
 	return "account_activities"
 }
 
-func (a *AccountActivity) SetVolumeETH(value *big.Int) {
-	if value != nil && value.Cmp(big.NewInt(0)) > 0 {
-		a.VolumeETH = value.String()
-		if a.VolumeETH == "" {
-			a.VolumeETH = "0"
-		}
-	} else {
-		a.VolumeETH = "0"
-	}
+func GetPeriodStart(t time.Time) time.Time {
+	return utils.PeriodStart(t)
 }
 
-func (a *AccountActivity) GetVolumeETH() *big.Int {
-	value, ok := big.NewInt(0).SetString(a.VolumeETH, 10)
-	if !ok {
-		return big.NewInt(0)
-	}
-	return value
+// GetNetFlowETH возвращает чистый поток ETH за период (полученный минус отправленный объем)
+func (a *AccountActivity) GetNetFlowETH() *big.Int {
+	return new(big.Int).Sub(a.VolumeETHReceived.Int(), a.VolumeETH.Int())
 }
 
-func GetPeriodStart(t time.Time) time.Time {
-	return t.Truncate(15 * time.Second)
+// GetNetTokenFlow возвращает чистый поток токенов за период (полученный минус отправленный объем)
+func (a *AccountActivity) GetNetTokenFlow() *big.Int {
+	return new(big.Int).Sub(a.TokenVolumeReceived.Int(), a.TokenVolume.Int())
+}
+
+// GetAvgGasPrice возвращает среднюю цену газа за период, взвешенную по израсходованному газу
+func (a *AccountActivity) GetAvgGasPrice() *big.Int {
+	return AvgGasPrice(a.FeesPaid, a.GasUsed)
+}
+
+// GetFeeToValueRatio возвращает отношение уплаченных комиссий к отправленному объему ETH за период
+func (a *AccountActivity) GetFeeToValueRatio() float64 {
+	return FeeToValueRatio(a.FeesPaid, a.VolumeETH)
+}
+
+// AccountCounterparty - контрагент аккаунта в базовом периоде.
+// По этим записям считается число различных контрагентов за период, срез и все время
+type AccountCounterparty struct {
+	ID           uint      `gorm:"primarykey" json:"-"`
+	Address      string    `gorm:"not null;type:char(42);uniqueIndex:idx_counterparty_address_period" json:"address"`
+	Period       time.Time `gorm:"not null;uniqueIndex:idx_counterparty_address_period;index" json:"period"`
+	Counterparty string    `gorm:"not null;type:char(42);uniqueIndex:idx_counterparty_address_period" json:"counterparty"`
+}
+
+func (AccountCounterparty) TableName() string {
+	return "account_counterparties"
+}
+
+// Разрешения срезов активности, собираемых из базовых периодов
+const (
+	ActivityResolutionBase   = "base" // базовый период, строки account_activities
+	ActivityResolutionMinute = "1m"
+	ActivityResolutionHour   = "1h"
+	ActivityResolutionDay    = "1d"
+)
+
+// AccountActivityRollup - активность аккаунта, агрегированная за минуту, час или сутки
+type AccountActivityRollup struct {
+	ID               uint      `gorm:"primarykey" json:"-"`
+	Address          string    `gorm:"not null;type:char(42);uniqueIndex:idx_activity_rollup_address_period" json:"address"`
+	Resolution       string    `gorm:"not null;type:varchar(4);uniqueIndex:idx_activity_rollup_address_period" json:"resolution"`
+	Period           time.Time `gorm:"not null;uniqueIndex:idx_activity_rollup_address_period;index" json:"period"`
+	TransactionCount uint32    `gorm:"not null;default:0" json:"transaction_count"`
+	VolumeETH        BigInt    `gorm:"not null;default:0" json:"volume_eth"`
+	TokenTransfers   uint32    `gorm:"not null;default:0" json:"token_transfers"`
+	TokenVolume      BigInt    `gorm:"not null;default:0" json:"token_volume"`
+	// Входящая активность
+	ReceivedCount          uint32 `gorm:"not null;default:0" json:"received_count"`
+	VolumeETHReceived      BigInt `gorm:"not null;default:0" json:"volume_eth_received"`
+	TokenTransfersReceived uint32 `gorm:"not null;default:0" json:"token_transfers_received"`
+	TokenVolumeReceived    BigInt `gorm:"not null;default:0" json:"token_volume_received"`
+	Counterparties         uint32 `gorm:"not null;default:0" json:"counterparties"` // различные контрагенты за весь срез
+	// Комиссии; перцентили цены газа считаются по транзакциям всего среза
+	FeesPaid    BigInt    `gorm:"not null;default:0" json:"fees_paid"`
+	GasUsed     uint64    `gorm:"not null;default:0" json:"gas_used"`
+	GasPriceP50 BigInt    `gorm:"not null;default:0" json:"gas_price_p50"`
+	GasPriceP90 BigInt    `gorm:"not null;default:0" json:"gas_price_p90"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (AccountActivityRollup) TableName() string {
+	return "account_activity_rollups"
+}
+
+// GetNetFlowETH возвращает чистый поток ETH за срез (полученный минус отправленный объем)
+func (a *AccountActivityRollup) GetNetFlowETH() *big.Int {
+	return new(big.Int).Sub(a.VolumeETHReceived.Int(), a.VolumeETH.Int())
+}
+
+// GetNetTokenFlow возвращает чистый поток токенов за срез (полученный минус отправленный объем)
+func (a *AccountActivityRollup) GetNetTokenFlow() *big.Int {
+	return new(big.Int).Sub(a.TokenVolumeReceived.Int(), a.TokenVolume.Int())
+}
+
+// GetAvgGasPrice возвращает среднюю цену газа за срез, взвешенную по израсходованному газу
+func (a *AccountActivityRollup) GetAvgGasPrice() *big.Int {
+	return AvgGasPrice(a.FeesPaid, a.GasUsed)
+}
+
+// GetFeeToValueRatio возвращает отношение уплаченных комиссий к отправленному объему ETH за срез
+func (a *AccountActivityRollup) GetFeeToValueRatio() float64 {
+	return FeeToValueRatio(a.FeesPaid, a.VolumeETH)
+}
+
+// ResolutionDuration возвращает длительность периода разрешения
+func ResolutionDuration(resolution string) time.Duration {
+	switch resolution {
+	case ActivityResolutionMinute:
+		return time.Minute
+	case ActivityResolutionHour:
+		return time.Hour
+	case ActivityResolutionDay:
+		return 24 * time.Hour
+	default:
+		return utils.ActivityPeriod()
+	}
 }
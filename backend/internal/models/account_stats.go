@@ -12,42 +12,60 @@ type AccountStats struct {
 	ERC20Transactions uint64 `gorm:"default:0"`
 	LastActivityTime  *time.Time
 	FirstActivityTime *time.Time
-	TotalVolumeETH    string `gorm:"default:'0'"`
-	ETHBalance        string `gorm:"default:'0'"` // Баланс ETH
+	TotalVolumeETH    BigInt `gorm:"not null;default:0"`
+	ETHBalance        BigInt `gorm:"not null;default:0"` // Баланс ETH
 	UniqueTokensCount uint32 `gorm:"default:0"`
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	// Объемы отслеживаемого токена за все время в минимальных единицах
+	TokenVolumeSent     BigInt `gorm:"not null;default:0"`
+	TokenVolumeReceived BigInt `gorm:"not null;default:0"`
+	// Входящая активность за все время
+	ReceivedTransactions      uint64 `gorm:"not null;default:0"`
+	TotalVolumeETHReceived    BigInt `gorm:"not null;default:0"`
+	ERC20TransactionsReceived uint64 `gorm:"not null;default:0"`
+	Counterparties            uint64 `gorm:"not null;default:0"` // различные контрагенты за все время
+	// Комиссии за все время
+	TotalFeesPaid   BigInt `gorm:"not null;default:0"`
+	TotalGasUsed    uint64 `gorm:"not null;default:0"`
+	ContractGasUsed uint64 `gorm:"not null;default:0"` // газ, израсходованный на вызовы контрактов
+	GasPriceP50     BigInt `gorm:"not null;default:0"`
+	GasPriceP90     BigInt `gorm:"not null;default:0"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
-func (a *AccountStats) SetTotalVolumeETH(value *big.Int) {
-	if value != nil {
-		a.TotalVolumeETH = value.String()
-	} else {
-		a.TotalVolumeETH = "0"
-	}
+// GetAvgGasPrice возвращает среднюю цену газа за все время, взвешенную по израсходованному газу
+func (a *AccountStats) GetAvgGasPrice() *big.Int {
+	return AvgGasPrice(a.TotalFeesPaid, a.TotalGasUsed)
+}
+
+// GetFeeToValueRatio возвращает отношение уплаченных комиссий к отправленному объему ETH за все время
+func (a *AccountStats) GetFeeToValueRatio() float64 {
+	return FeeToValueRatio(a.TotalFeesPaid, a.TotalVolumeETH)
 }
 
-func (a *AccountStats) GetTotalVolumeETH() *big.Int {
-	value, ok := big.NewInt(0).SetString(a.TotalVolumeETH, 10)
-	if !ok {
+// AvgGasPrice возвращает среднюю цену газа: комиссии, деленные на газ (0, если газа нет)
+func AvgGasPrice(fees BigInt, gasUsed uint64) *big.Int {
+	if gasUsed == 0 {
 		return big.NewInt(0)
 	}
-	return value
+	return new(big.Int).Quo(fees.Int(), new(big.Int).SetUint64(gasUsed))
 }
 
-// Методы для работы с балансом ETH
-func (a *AccountStats) SetETHBalance(value *big.Int) {
-	if value != nil {
-		a.ETHBalance = value.String()
-	} else {
-		a.ETHBalance = "0"
+// FeeToValueRatio возвращает отношение комиссий к объему (0, если объема нет)
+func FeeToValueRatio(fees, value BigInt) float64 {
+	if value.Sign() == 0 {
+		return 0
 	}
+	ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(fees.Int()), new(big.Float).SetInt(value.Int())).Float64()
+	return ratio
 }
 
-func (a *AccountStats) GetETHBalance() *big.Int {
-	value, ok := big.NewInt(0).SetString(a.ETHBalance, 10)
-	if !ok {
-		return big.NewInt(0)
-	}
-	return value
+// GetVolumeETHNet возвращает чистый поток ETH за все время (полученный минус отправленный объем)
+func (a *AccountStats) GetVolumeETHNet() *big.Int {
+	return new(big.Int).Sub(a.TotalVolumeETHReceived.Int(), a.TotalVolumeETH.Int())
+}
+
+// GetTokenVolumeNet возвращает чистый объем токена (полученный минус отправленный)
+func (a *AccountStats) GetTokenVolumeNet() *big.Int {
+	return new(big.Int).Sub(a.TokenVolumeReceived.Int(), a.TokenVolumeSent.Int())
 }
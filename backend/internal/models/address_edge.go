@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// EdgeAssetETH - актив ребра для переводов ETH; для токенов актив - адрес контракта
+const EdgeAssetETH = "ETH"
+
+// AddressEdge - направленное взвешенное ребро графа взаимодействий: переводы актива от From к To
+type AddressEdge struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	From             string    `gorm:"not null;type:char(42);uniqueIndex:idx_address_edge" json:"from"`
+	To               string    `gorm:"not null;type:char(42);uniqueIndex:idx_address_edge;index" json:"to"`
+	Asset            string    `gorm:"not null;type:varchar(42);uniqueIndex:idx_address_edge" json:"asset"` // EdgeAssetETH или адрес токена
+	TransactionCount uint64    `gorm:"not null;default:0" json:"transaction_count"`
+	Volume           BigInt    `gorm:"not null;default:0" json:"volume"` // в wei или минимальных единицах токена
+	FirstSeen        time.Time `gorm:"not null" json:"first_seen"`
+	LastSeen         time.Time `gorm:"not null" json:"last_seen"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (AddressEdge) TableName() string {
+	return "address_edges"
+}
+
+// Other возвращает второй конец ребра относительно address
+func (e *AddressEdge) Other(address string) string {
+	if e.From == address {
+		return e.To
+	}
+	return e.From
+}
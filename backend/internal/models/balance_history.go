@@ -0,0 +1,42 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// NativeAsset - условный адрес токена для записей леджера в ETH
+const NativeAsset = ZeroAddress
+
+// LogIndex для записей леджера, не связанных с событием
+const (
+	LogIndexNative     = -1 // изменение баланса ETH транзакцией (value, газ и внутренние переводы)
+	LogIndexOpening    = -2 // начальный баланс на момент начала индексации
+	LogIndexBlockFees  = -3 // чаевые за газ получателю комиссий блока
+	LogIndexWithdrawal = -4 // вывод из beacon chain (EIP-4895)
+	LogIndexCorrection = -5 // поправка по результату сверки с BalanceAt
+)
+
+// TxIndexBlockEnd - TxIndex записей уровня блока: они применяются после всех транзакций блока.
+// TransactionHash таких записей - хеш блока
+const TxIndexBlockEnd = math.MaxInt32
+
+// BalanceChange - запись леджера изменений баланса ETH или токена.
+// Balance - баланс после применения Delta с учетом всех предыдущих записей
+type BalanceChange struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Address         string    `gorm:"not null;type:char(42);uniqueIndex:idx_balance_change_unique;index:idx_balance_change_block,priority:1" json:"address"`
+	TokenAddress    string    `gorm:"not null;type:char(42);uniqueIndex:idx_balance_change_unique;index:idx_balance_change_block,priority:2" json:"token_address"`
+	BlockNumber     uint64    `gorm:"not null;index:idx_balance_change_block,priority:3" json:"block_number"`
+	TxIndex         uint      `gorm:"not null;default:0" json:"tx_index"`
+	TransactionHash string    `gorm:"not null;type:varchar(66);uniqueIndex:idx_balance_change_unique" json:"transaction_hash"`
+	LogIndex        int       `gorm:"not null;uniqueIndex:idx_balance_change_unique" json:"log_index"`
+	Delta           BigInt    `gorm:"not null" json:"delta"`
+	Balance         BigInt    `gorm:"not null;default:0" json:"balance"`
+	Timestamp       time.Time `gorm:"not null;index" json:"timestamp"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (BalanceChange) TableName() string {
+	return "balance_changes"
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// BigIntSQLType - тип колонки для сумм: 78 знаков вмещают любое uint256 (и знаковые разницы)
+const BigIntSQLType = "numeric(78,0)"
+
+// BigInt - целое произвольной точности для сумм в wei и минимальных единицах токенов.
+// Хранится в numeric(78,0), поэтому сортировка и агрегация выполняются на стороне SQL.
+// Нулевое значение BigInt равно 0
+type BigInt struct {
+	value *big.Int
+}
+
+// NewBigInt создает BigInt из big.Int (nil означает 0); значение копируется
+func NewBigInt(value *big.Int) BigInt {
+	if value == nil {
+		return BigInt{}
+	}
+	return BigInt{value: new(big.Int).Set(value)}
+}
+
+// NewBigIntFromInt64 создает BigInt из int64
+func NewBigIntFromInt64(value int64) BigInt {
+	return BigInt{value: big.NewInt(value)}
+}
+
+// ParseBigInt разбирает десятичную строку; дробная часть numeric (например "10.0") отбрасывается
+func ParseBigInt(value string) (BigInt, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return BigInt{}, nil
+	}
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	parsed, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return BigInt{}, fmt.Errorf("некорректное целое число: %q", value)
+	}
+	return BigInt{value: parsed}, nil
+}
+
+// Int возвращает копию значения как big.Int
+func (b BigInt) Int() *big.Int {
+	if b.value == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(b.value)
+}
+
+// Sign возвращает -1, 0 или +1
+func (b BigInt) Sign() int {
+	if b.value == nil {
+		return 0
+	}
+	return b.value.Sign()
+}
+
+// Cmp сравнивает значения: -1, 0 или +1
+func (b BigInt) Cmp(other BigInt) int {
+	return b.Int().Cmp(other.Int())
+}
+
+// Add возвращает сумму значений
+func (b BigInt) Add(value *big.Int) BigInt {
+	result := b.Int()
+	if value != nil {
+		result.Add(result, value)
+	}
+	return BigInt{value: result}
+}
+
+// Sub возвращает разность значений
+func (b BigInt) Sub(value *big.Int) BigInt {
+	result := b.Int()
+	if value != nil {
+		result.Sub(result, value)
+	}
+	return BigInt{value: result}
+}
+
+func (b BigInt) String() string {
+	if b.value == nil {
+		return "0"
+	}
+	return b.value.String()
+}
+
+// Value реализует driver.Valuer
+func (b BigInt) Value() (driver.Value, error) {
+	return b.String(), nil
+}
+
+// Scan реализует sql.Scanner
+func (b *BigInt) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*b = BigInt{}
+		return nil
+	case string:
+		parsed, err := ParseBigInt(v)
+		if err != nil {
+			return err
+		}
+		*b = parsed
+		return nil
+	case []byte:
+		parsed, err := ParseBigInt(string(v))
+		if err != nil {
+			return err
+		}
+		*b = parsed
+		return nil
+	case int64:
+		*b = NewBigIntFromInt64(v)
+		return nil
+	default:
+		return fmt.Errorf("неподдерживаемый тип для BigInt: %T", src)
+	}
+}
+
+// GormDataType задает тип колонки для миграций
+func (BigInt) GormDataType() string {
+	return BigIntSQLType
+}
+
+// GormDBDataType задает тип колонки для конкретной СУБД
+func (BigInt) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return BigIntSQLType
+}
+
+// MarshalJSON сериализует значение строкой, чтобы не терять точность в JavaScript
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON принимает как строку, так и число
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = BigInt{}
+		return nil
+	}
+
+	value := strings.Trim(string(data), `"`)
+	parsed, err := ParseBigInt(value)
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
@@ -7,60 +7,26 @@ import (
 
 // Transaction - оптимизированная модель для транзакций Ethereum
 type Transaction struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Hash        string    `gorm:"not null;type:char(66)" json:"hash"` // 0x + 64 hex chars
-	BlockNumber uint64    `gorm:"not null;index:idx_transactions_block_time" json:"block_number"`
-	From        string    `gorm:"not null;index;type:char(42);check:\"from\" != '0x0000000000000000000000000000000000000000' AND \"from\" != ''" json:"from"` // 0x + 40 hex chars
-	To          string    `gorm:"not null;index;type:char(42);check:\"to\" != '0x0000000000000000000000000000000000000000' AND \"to\" != ''" json:"to"`       // 0x + 40 hex chars
-	Value       string    `gorm:"not null;type:numeric" json:"value"`                                                                                         // Используем numeric для больших чисел
-	GasUsed     uint64    `gorm:"not null" json:"gas_used"`
-	GasPrice    string    `gorm:"not null;type:numeric" json:"gas_price"`                      // numeric для точности
-	Status      uint64    `gorm:"not null;index" json:"status"`                                // Индекс для фильтрации успешных/неуспешных
-	Timestamp   time.Time `gorm:"not null;index:idx_transactions_block_time" json:"timestamp"` // Составной индекс с BlockNumber
-	CreatedAt   time.Time `gorm:"index" json:"created_at"`                                     // Индекс для сортировки по времени создания
-	UpdatedAt   time.Time `json:"updated_at"`
-}
-
-// GetValue возвращает значение Value как big.Int
-func (t *Transaction) GetValue() *big.Int {
-	value, ok := big.NewInt(0).SetString(t.Value, 10)
-	if !ok {
-		return big.NewInt(0)
-	}
-	return value
-}
-
-// SetValue устанавливает значение Value из big.Int
-func (t *Transaction) SetValue(value *big.Int) {
-	if value != nil {
-		t.Value = value.String()
-	} else {
-		t.Value = "0"
-	}
-}
-
-// GetGasPrice возвращает цену газа как big.Int
-func (t *Transaction) GetGasPrice() *big.Int {
-	price, ok := big.NewInt(0).SetString(t.GasPrice, 10)
-	if !ok {
-		return big.NewInt(0)
-	}
-	return price
-}
-
-// SetGasPrice устанавливает цену газа из big.Int
-func (t *Transaction) SetGasPrice(price *big.Int) {
-	if price != nil {
-		t.GasPrice = price.String()
-	} else {
-		t.GasPrice = "0"
-	}
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Hash            string    `gorm:"uniqueIndex;not null;type:char(66)" json:"hash"` // 0x + 64 hex chars
+	BlockNumber     uint64    `gorm:"not null;index:idx_transactions_block_time" json:"block_number"`
+	From            string    `gorm:"not null;index;type:char(42);check:\"from\" != '0x0000000000000000000000000000000000000000' AND \"from\" != ''" json:"from"` // 0x + 40 hex chars
+	To              string    `gorm:"not null;index;type:char(42);check:\"to\" != '0x0000000000000000000000000000000000000000'" json:"to"`                        // пустой у транзакций деплоя
+	Value           BigInt    `gorm:"not null" json:"value"`
+	ContractAddress string    `gorm:"not null;default:'';type:char(42)" json:"contract_address,omitempty"` // адрес созданного контракта у транзакций деплоя
+	GasUsed         uint64    `gorm:"not null" json:"gas_used"`
+	GasPrice        BigInt    `gorm:"not null" json:"gas_price"`                                   // фактическая цена газа (для EIP-1559 - effective gas price)
+	ContractCall    bool      `gorm:"not null;default:false" json:"contract_call"`                 // транзакция с calldata - вызов контракта
+	Status          uint64    `gorm:"not null;index" json:"status"`                                // Индекс для фильтрации успешных/неуспешных
+	Timestamp       time.Time `gorm:"not null;index:idx_transactions_block_time" json:"timestamp"` // Составной индекс с BlockNumber
+	CreatedAt       time.Time `gorm:"index" json:"created_at"`                                     // Индекс для сортировки по времени создания
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // GetTotalGasCost возвращает общую стоимость газа (GasUsed * GasPrice)
 func (t *Transaction) GetTotalGasCost() *big.Int {
-	gasPrice := t.GetGasPrice()
-	gasUsed := big.NewInt(int64(t.GasUsed))
+	gasPrice := t.GasPrice.Int()
+	gasUsed := new(big.Int).SetUint64(t.GasUsed)
 	return gasPrice.Mul(gasPrice, gasUsed)
 }
 
@@ -69,9 +35,17 @@ func (t *Transaction) IsSuccessful() bool {
 	return t.Status == 1
 }
 
+// TransferredValue возвращает фактически переведенную сумму: revert транзакция value не переводит
+func (t *Transaction) TransferredValue() BigInt {
+	if !t.IsSuccessful() {
+		return BigInt{}
+	}
+	return t.Value
+}
+
 // GetTotalCost возвращает общую стоимость транзакции (Value + GasCost)
 func (t *Transaction) GetTotalCost() *big.Int {
-	value := t.GetValue()
+	value := t.Value.Int()
 	gasCost := t.GetTotalGasCost()
 	return value.Add(value, gasCost)
 }
@@ -79,30 +53,39 @@ func (t *Transaction) GetTotalCost() *big.Int {
 // ERC20Transfer - оптимизированная модель для ERC20 трансферов
 type ERC20Transfer struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
-	TransactionHash string    `gorm:"uniqueIndex;not null;type:char(66)" json:"transaction_hash"`
+	TransactionHash string    `gorm:"not null;type:char(66);uniqueIndex:idx_erc20_tx_log,priority:1" json:"transaction_hash"`
+	LogIndex        uint      `gorm:"not null;default:0;uniqueIndex:idx_erc20_tx_log,priority:2" json:"log_index"` // индекс лога в блоке: одна транзакция может выпустить несколько Transfer
 	ContractAddress string    `gorm:"not null;index;type:char(42)" json:"contract_address"`
-	From            string    `gorm:"not null;index;type:char(42);check:\"from\" != '0x0000000000000000000000000000000000000000' AND \"from\" != ''" json:"from"`
-	To              string    `gorm:"not null;index;type:char(42);check:\"to\" != '0x0000000000000000000000000000000000000000' AND \"to\" != ''" json:"to"`
-	Value           string    `gorm:"not null;type:numeric" json:"value"` // numeric для точности
-	BlockNumber     uint64    `gorm:"not null;index:idx_erc20_block_time" json:"block_number"`
-	CreatedAt       time.Time `gorm:"index:idx_erc20_block_time" json:"created_at"` // Составной индекс с BlockNumber
+	From            string    `gorm:"not null;index;type:char(42);check:\"from\" != ''" json:"from"` // нулевой адрес для mint
+	To              string    `gorm:"not null;index;type:char(42);check:\"to\" != ''" json:"to"`     // нулевой адрес для burn
+	Value           BigInt    `gorm:"not null" json:"value"`
+	Kind            string    `gorm:"not null;index;type:varchar(8);default:'transfer'" json:"kind"` // transfer, mint или burn
+	BlockNumber     uint64    `gorm:"not null;index:idx_erc20_block_timestamp" json:"block_number"`
+	Timestamp       time.Time `gorm:"not null;index:idx_erc20_block_timestamp;index" json:"timestamp"` // время блока; составной индекс с BlockNumber
+	Accounted       bool      `gorm:"not null;default:false" json:"-"`                                 // учтен в статистике, балансах и графе; выставляет только анализатор
+	Recorded        bool      `gorm:"not null;default:false" json:"recorded"`                          // учтен в статистике AnalyzerToken (transfer/transferWithInfo, не transferFrom)
+	CreatedAt       time.Time `json:"created_at"`                                                      // время записи в БД
 }
 
-// GetValue возвращает значение Value как big.Int
-func (e *ERC20Transfer) GetValue() *big.Int {
-	value, ok := big.NewInt(0).SetString(e.Value, 10)
-	if !ok {
-		return big.NewInt(0)
-	}
-	return value
-}
+// Виды ERC20 трансферов
+const (
+	TransferKindTransfer = "transfer"
+	TransferKindMint     = "mint"
+	TransferKindBurn     = "burn"
+)
+
+// ZeroAddress - нулевой адрес, используемый токенами для mint и burn
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
 
-// SetValue устанавливает значение Value из big.Int
-func (e *ERC20Transfer) SetValue(value *big.Int) {
-	if value != nil {
-		e.Value = value.String()
-	} else {
-		e.Value = "0"
+// TransferKindOf определяет вид трансфера по адресам отправителя и получателя
+func TransferKindOf(from, to string) string {
+	switch {
+	case from == ZeroAddress:
+		return TransferKindMint
+	case to == ZeroAddress:
+		return TransferKindBurn
+	default:
+		return TransferKindTransfer
 	}
 }
 
@@ -115,6 +98,14 @@ type AnalyzerState struct {
 	UpdatedAt               time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// ListenerCheckpoint - последний блок, события которого слушатель контракта передал в приемник.
+// После перезапуска слушатель дозагружает события с этого блока
+type ListenerCheckpoint struct {
+	ContractAddress string    `gorm:"primaryKey;type:char(42)" json:"contract_address"`
+	BlockNumber     uint64    `gorm:"not null" json:"block_number"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
 // ContractTransaction представляет транзакцию контракта
 type ContractTransaction struct {
 	ID              uint64    `gorm:"primaryKey;autoIncrement"`
@@ -122,10 +113,21 @@ type ContractTransaction struct {
 	TransactionHash string    `gorm:"uniqueIndex;not null"`
 	From            string    `gorm:"index;not null"`
 	To              string    `gorm:"index;not null"`
-	Value           string    `gorm:"not null"`
+	Value           BigInt    `gorm:"not null"`
 	Method          string    `gorm:"not null"`
+	MethodID        string    `gorm:"index;type:varchar(10)"` // селектор метода (0x + 4 байта)
+	MethodSignature string    // полная сигнатура, если метод удалось определить
+	DecodedArgs     string    `gorm:"type:jsonb"` // аргументы вызова в JSON
 	BlockNumber     uint64    `gorm:"index;not null"`
 	Timestamp       time.Time `gorm:"index;not null"`
 	GasUsed         uint64    `gorm:"not null"`
 	Status          uint64    `gorm:"not null"`
 }
+
+// MethodSignature - запись локальной базы сигнатур методов
+type MethodSignature struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Selector  string    `gorm:"not null;index;type:char(10)" json:"selector"`
+	Signature string    `gorm:"uniqueIndex;not null" json:"signature"`
+	CreatedAt time.Time `json:"created_at"`
+}
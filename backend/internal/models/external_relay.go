@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Статусы ретрансляции внешней транзакции в AnalyzerToken.recordExternalTransaction
+const (
+	RelayStatusPending   = "pending"   // ожидает отправки
+	RelayStatusSent      = "sent"      // отправлена, ждет включения в блок
+	RelayStatusConfirmed = "confirmed" // успешно записана в контракт
+	RelayStatusFailed    = "failed"    // исчерпаны попытки
+)
+
+// ExternalTransactionRelay - очередь ретрансляции внешних транзакций в контракт
+type ExternalTransactionRelay struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	TransactionHash string     `gorm:"uniqueIndex;not null;type:char(66)" json:"transaction_hash"`
+	From            string     `gorm:"not null;type:char(42)" json:"from"`
+	To              string     `gorm:"not null;type:char(42)" json:"to"`
+	Value           BigInt     `gorm:"not null" json:"value"`
+	MethodID        string     `gorm:"not null;type:char(10)" json:"method_id"` // 0x + 4 байта селектора
+	Success         bool       `gorm:"not null" json:"success"`
+	GasUsed         uint64     `gorm:"not null" json:"gas_used"`
+	BlockNumber     uint64     `gorm:"not null;index" json:"block_number"`
+	Status          string     `gorm:"not null;index;default:'pending'" json:"status"`
+	RelayTxHash     string     `gorm:"type:char(66)" json:"relay_tx_hash"`
+	RelayNonce      *uint64    `json:"relay_nonce"`
+	Attempts        uint32     `gorm:"not null;default:0" json:"attempts"`
+	LastError       string     `json:"last_error"`
+	NextAttemptAt   *time.Time `gorm:"index" json:"next_attempt_at"`
+	SentAt          *time.Time `json:"sent_at"` // время последней отправки, от него отсчитывается relaySentTimeout
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (ExternalTransactionRelay) TableName() string {
+	return "external_transaction_relays"
+}
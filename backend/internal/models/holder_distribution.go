@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// HolderDistributionSnapshot - распределение токена между держателями на блоке
+type HolderDistributionSnapshot struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ContractAddress string    `gorm:"not null;type:char(42);uniqueIndex:idx_holder_distribution_contract_block" json:"contract_address"`
+	BlockNumber     uint64    `gorm:"not null;uniqueIndex:idx_holder_distribution_contract_block" json:"block_number"`
+	HolderCount     uint64    `gorm:"not null;default:0" json:"holder_count"` // адреса с ненулевым балансом
+	TotalBalance    BigInt    `gorm:"not null;default:0" json:"total_balance"`
+	Top10Share      float64   `gorm:"not null;default:0" json:"top10_share"`  // доля 10 крупнейших держателей, 0..1
+	Top100Share     float64   `gorm:"not null;default:0" json:"top100_share"` // доля 100 крупнейших держателей, 0..1
+	Gini            float64   `gorm:"not null;default:0" json:"gini"`         // коэффициент Джини, 0 - равномерно, 1 - у одного держателя
+	Nakamoto        uint64    `gorm:"not null;default:0" json:"nakamoto"`     // минимум держателей, владеющих более чем половиной
+	FromLedger      bool      `gorm:"not null;default:false" json:"from_ledger"`
+	SnapshotAt      time.Time `gorm:"not null;index" json:"snapshot_at"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	TopHolders []HolderDistributionEntry `gorm:"foreignKey:SnapshotID;constraint:OnDelete:CASCADE" json:"top_holders,omitempty"`
+}
+
+func (HolderDistributionSnapshot) TableName() string {
+	return "holder_distribution_snapshots"
+}
+
+// HolderDistributionEntry - крупный держатель в снимке распределения
+type HolderDistributionEntry struct {
+	ID         uint    `gorm:"primaryKey" json:"-"`
+	SnapshotID uint    `gorm:"not null;uniqueIndex:idx_holder_entry_snapshot_rank" json:"-"`
+	Rank       uint    `gorm:"not null;uniqueIndex:idx_holder_entry_snapshot_rank" json:"rank"`
+	Address    string  `gorm:"not null;type:char(42);index" json:"address"`
+	Balance    BigInt  `gorm:"not null" json:"balance"`
+	Share      float64 `gorm:"not null" json:"share"`
+}
+
+func (HolderDistributionEntry) TableName() string {
+	return "holder_distribution_entries"
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"math/big"
+	"time"
+)
+
+// Метрики, сверяемые между AnalyzerToken и базой данных.
+// total_* сравниваются с AccountStats, recorded_* - вспомогательный сигнал по сырым трансферам с признаком recorded
+const (
+	ReconcileMetricTotalTransactions    = "total_transactions"
+	ReconcileMetricTotalSent            = "total_sent"
+	ReconcileMetricTotalReceived        = "total_received"
+	ReconcileMetricExternalTransactions = "external_transactions"
+	ReconcileMetricPeriodSent           = "period_sent"
+	ReconcileMetricPeriodReceived       = "period_received"
+	ReconcileMetricRecordedTransactions = "recorded_transactions"
+	ReconcileMetricRecordedSent         = "recorded_sent"
+	ReconcileMetricRecordedReceived     = "recorded_received"
+)
+
+// StatsDiscrepancy - расхождение между статистикой контракта и данными в БД
+type StatsDiscrepancy struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	RunID           string    `gorm:"not null;index;type:varchar(36)" json:"run_id"`
+	ContractAddress string    `gorm:"not null;index;type:char(42)" json:"contract_address"`
+	Address         string    `gorm:"not null;index;type:char(42)" json:"address"`
+	Metric          string    `gorm:"not null;type:varchar(32)" json:"metric"`
+	OnChainValue    BigInt    `gorm:"not null" json:"on_chain_value"`
+	DBValue         BigInt    `gorm:"not null" json:"db_value"`
+	Difference      BigInt    `gorm:"not null" json:"difference"` // on-chain минус БД
+	DriftPercent    float64   `gorm:"not null" json:"drift_percent"`
+	BlockNumber     uint64    `gorm:"not null" json:"block_number"`
+	CheckedAt       time.Time `gorm:"not null;index" json:"checked_at"`
+}
+
+func (StatsDiscrepancy) TableName() string {
+	return "stats_discrepancies"
+}
+
+// NewStatsDiscrepancy создает запись о расхождении и вычисляет дрейф в процентах от on-chain значения
+func NewStatsDiscrepancy(metric string, onChain, db *big.Int) StatsDiscrepancy {
+	diff := new(big.Int).Sub(onChain, db)
+
+	var drift float64
+	if onChain.Sign() != 0 {
+		ratio := new(big.Rat).SetFrac(new(big.Int).Abs(diff), new(big.Int).Abs(onChain))
+		drift, _ = ratio.Float64()
+		drift *= 100
+	} else if diff.Sign() != 0 {
+		drift = 100
+	}
+
+	return StatsDiscrepancy{
+		Metric:       metric,
+		OnChainValue: NewBigInt(onChain),
+		DBValue:      NewBigInt(db),
+		Difference:   NewBigInt(diff),
+		DriftPercent: drift,
+	}
+}
+
+// BalanceCorrection - исправление сохраненного баланса токена по on-chain balanceOf
+type BalanceCorrection struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	RunID          string    `gorm:"not null;index;type:varchar(36)" json:"run_id"`
+	TokenAddress   string    `gorm:"not null;index;type:char(42)" json:"token_address"`
+	Address        string    `gorm:"not null;index;type:char(42)" json:"address"`
+	StoredBalance  BigInt    `gorm:"not null" json:"stored_balance"`
+	OnChainBalance BigInt    `gorm:"not null" json:"on_chain_balance"`
+	Difference     BigInt    `gorm:"not null" json:"difference"` // on-chain минус сохраненный
+	BlockNumber    uint64    `gorm:"not null" json:"block_number"`
+	CorrectedAt    time.Time `gorm:"not null;index" json:"corrected_at"`
+}
+
+func (BalanceCorrection) TableName() string {
+	return "balance_corrections"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// TotalSupplySnapshot - эмиссия токена на блоке, где были mint или burn.
+// TotalSupply считается по проиндексированным событиям от начальной записи (Opening) и сверяется с totalSupply контракта
+type TotalSupplySnapshot struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	ContractAddress string     `gorm:"not null;type:char(42);uniqueIndex:idx_supply_contract_block" json:"contract_address"`
+	BlockNumber     uint64     `gorm:"not null;uniqueIndex:idx_supply_contract_block" json:"block_number"`
+	Minted          BigInt     `gorm:"not null;default:0" json:"minted"`       // выпущено на этом блоке
+	Burned          BigInt     `gorm:"not null;default:0" json:"burned"`       // сожжено на этом блоке
+	TotalSupply     BigInt     `gorm:"not null;default:0" json:"total_supply"` // накопленная эмиссия по событиям
+	OnChainSupply   *BigInt    `json:"on_chain_supply"`                        // totalSupply контракта на блоке
+	Verified        bool       `gorm:"not null;default:false;index" json:"verified"`
+	Matches         bool       `gorm:"not null;default:false" json:"matches"`
+	Opening         bool       `gorm:"not null;default:false" json:"opening"` // totalSupply контракта на блоке перед первым проиндексированным трансфером
+	CheckedAt       *time.Time `json:"checked_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (TotalSupplySnapshot) TableName() string {
+	return "total_supply_history"
+}
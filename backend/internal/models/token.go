@@ -0,0 +1,39 @@
+package models
+
+import (
+	"backend/internal/utils"
+	"math/big"
+	"time"
+)
+
+// Token - метаданные ERC20 токена
+type Token struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	ContractAddress string     `gorm:"uniqueIndex;not null;type:char(42)" json:"contract_address"`
+	Name            string     `gorm:"not null;default:''" json:"name"`
+	Symbol          string     `gorm:"not null;default:''" json:"symbol"`
+	Decimals        *uint8     `json:"decimals"` // nil - контракт не реализует decimals()
+	TotalSupply     BigInt     `gorm:"not null;default:0" json:"total_supply"`
+	SupplyUpdatedAt *time.Time `json:"supply_updated_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (Token) TableName() string {
+	return "tokens"
+}
+
+// GetDecimals возвращает количество десятичных знаков и признак того, что оно известно
+func (t *Token) GetDecimals() (uint8, bool) {
+	if t.Decimals == nil {
+		return 0, false
+	}
+	return *t.Decimals, true
+}
+
+// FormatAmount переводит сырое целое значение в единицы токена с символом.
+// Если decimals неизвестен, выводится сырое значение
+func (t *Token) FormatAmount(value *big.Int) string {
+	decimals, _ := t.GetDecimals()
+	return utils.FormatAmount(value, decimals, t.Symbol)
+}
@@ -2,11 +2,14 @@ package repositories
 
 import (
 	"backend/internal/models"
+	"backend/internal/utils"
+	"fmt"
 	"math/big"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AccountRepository struct {
@@ -18,29 +21,139 @@ func NewAccountRepository() *AccountRepository {
 }
 
 func (r *AccountRepository) GetOrCreateAccountStats(address string) (*models.AccountStats, error) {
-	var stats models.AccountStats
+	// Вставка с ON CONFLICT DO NOTHING безопасна при одновременном создании из нескольких горутин
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoNothing: true,
+	}).Create(&models.AccountStats{Address: address}).Error
+	if err != nil {
+		return nil, err
+	}
 
-	result := r.db.Where("address = ?", address).First(&stats)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			// Создаем новую запись
-			stats = models.AccountStats{
-				Address:        address,
-				TotalVolumeETH: "0",
-			}
-			if err := r.db.Create(&stats).Error; err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, result.Error
-		}
+	var stats models.AccountStats
+	if err := r.db.Where("address = ?", address).First(&stats).Error; err != nil {
+		return nil, err
 	}
 
 	return &stats, nil
 }
 
+// UpdateAccountStats сохраняет пересчитываемые метрики аккаунта.
+// Счетчики и объемы меняются только атомарно через RecordTransactionStats и RecordERC20Transaction
 func (r *AccountRepository) UpdateAccountStats(stats *models.AccountStats) error {
-	return r.db.Save(stats).Error
+	return r.db.Model(stats).
+		Select("eth_balance", "unique_tokens_count", "token_volume_sent", "token_volume_received", "counterparties",
+			"gas_price_p50", "gas_price_p90").
+		Updates(stats).Error
+}
+
+// RecordTransactionStats атомарно учитывает транзакцию в статистике аккаунта: отправителю увеличивает исходящие
+// счетчик и объем ETH и комиссии, получателю - входящие счетчик и объем, обоим сдвигает время активности.
+// Revert транзакция учитывается в счетчиках и комиссиях, но не в объемах
+func (r *AccountRepository) RecordTransactionStats(address string, isSender bool, tx *models.Transaction) error {
+	sent, received := 0, 0
+	sentVolume, receivedVolume, fees := models.BigInt{}, models.BigInt{}, models.BigInt{}
+	var gasUsed, contractGasUsed uint64
+	if isSender {
+		sent, sentVolume = 1, tx.TransferredValue()
+		fees, gasUsed = models.NewBigInt(tx.GetTotalGasCost()), tx.GasUsed
+		if tx.ContractCall {
+			contractGasUsed = tx.GasUsed
+		}
+	} else {
+		received, receivedVolume = 1, tx.TransferredValue()
+	}
+
+	return r.db.Exec(`
+		INSERT INTO account_stats (address, total_transactions, total_volume_eth, received_transactions, total_volume_eth_received,
+			total_fees_paid, total_gas_used, contract_gas_used, first_activity_time, last_activity_time, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+		ON CONFLICT (address) DO UPDATE SET
+			total_transactions = account_stats.total_transactions + EXCLUDED.total_transactions,
+			total_volume_eth = account_stats.total_volume_eth + EXCLUDED.total_volume_eth,
+			received_transactions = account_stats.received_transactions + EXCLUDED.received_transactions,
+			total_volume_eth_received = account_stats.total_volume_eth_received + EXCLUDED.total_volume_eth_received,
+			total_fees_paid = account_stats.total_fees_paid + EXCLUDED.total_fees_paid,
+			total_gas_used = account_stats.total_gas_used + EXCLUDED.total_gas_used,
+			contract_gas_used = account_stats.contract_gas_used + EXCLUDED.contract_gas_used,
+			first_activity_time = LEAST(COALESCE(account_stats.first_activity_time, EXCLUDED.first_activity_time), EXCLUDED.first_activity_time),
+			last_activity_time = GREATEST(COALESCE(account_stats.last_activity_time, EXCLUDED.last_activity_time), EXCLUDED.last_activity_time),
+			updated_at = NOW()
+	`, address, sent, sentVolume, received, receivedVolume, fees, gasUsed, contractGasUsed, tx.Timestamp, tx.Timestamp).Error
+}
+
+// RecordERC20Transaction атомарно увеличивает счетчик исходящих (isSender) или входящих ERC20 транзакций аккаунта
+func (r *AccountRepository) RecordERC20Transaction(address string, isSender bool, timestamp time.Time) error {
+	sent, received := 0, 1
+	if isSender {
+		sent, received = 1, 0
+	}
+
+	return r.db.Exec(`
+		INSERT INTO account_stats (address, erc20_transactions, erc20_transactions_received, total_volume_eth,
+			first_activity_time, last_activity_time, created_at, updated_at)
+		VALUES (?, ?, ?, 0, ?, ?, NOW(), NOW())
+		ON CONFLICT (address) DO UPDATE SET
+			erc20_transactions = account_stats.erc20_transactions + EXCLUDED.erc20_transactions,
+			erc20_transactions_received = account_stats.erc20_transactions_received + EXCLUDED.erc20_transactions_received,
+			first_activity_time = LEAST(COALESCE(account_stats.first_activity_time, EXCLUDED.first_activity_time), EXCLUDED.first_activity_time),
+			last_activity_time = GREATEST(COALESCE(account_stats.last_activity_time, EXCLUDED.last_activity_time), EXCLUDED.last_activity_time),
+			updated_at = NOW()
+	`, address, sent, received, timestamp, timestamp).Error
+}
+
+// GasPricePercentiles - медиана и 90-й перцентиль цены газа
+type GasPricePercentiles struct {
+	P50 models.BigInt
+	P90 models.BigInt
+}
+
+// GetGasPricePercentiles считает перцентили цены газа по всем транзакциям, отправленным аккаунтом
+func (r *AccountRepository) GetGasPricePercentiles(address string) (*GasPricePercentiles, error) {
+	var percentiles GasPricePercentiles
+	err := r.db.Model(&models.Transaction{}).
+		Select(`
+			COALESCE(percentile_disc(0.5) WITHIN GROUP (ORDER BY gas_price), 0) as p50,
+			COALESCE(percentile_disc(0.9) WITHIN GROUP (ORDER BY gas_price), 0) as p90
+		`).
+		Where("\"from\" = ?", address).
+		Scan(&percentiles).Error
+	return &percentiles, err
+}
+
+// ContractGasUsage - газ и комиссии, потраченные аккаунтом на вызовы одного контракта
+type ContractGasUsage struct {
+	Contract         string
+	TransactionCount uint64
+	GasUsed          uint64
+	FeesPaid         models.BigInt
+}
+
+// GetGasByContract возвращает расход газа аккаунта на вызовы контрактов за [from, to), начиная с самых затратных
+func (r *AccountRepository) GetGasByContract(address string, from, to time.Time) ([]ContractGasUsage, error) {
+	var usage []ContractGasUsage
+	err := r.db.Model(&models.Transaction{}).
+		Select(`
+			"to" as contract,
+			COUNT(*) as transaction_count,
+			COALESCE(SUM(gas_used), 0) as gas_used,
+			COALESCE(SUM(gas_used * gas_price), 0) as fees_paid
+		`).
+		Where("\"from\" = ? AND contract_call AND timestamp >= ? AND timestamp < ?", address, from, to).
+		Group("\"to\"").
+		Order("gas_used DESC").
+		Scan(&usage).Error
+	return usage, err
+}
+
+// CountCounterparties возвращает число различных контрагентов аккаунта за все время
+func (r *AccountRepository) CountCounterparties(address string) (uint64, error) {
+	var count int64
+	err := r.db.Model(&models.AccountCounterparty{}).
+		Where("address = ?", address).
+		Distinct("counterparty").
+		Count(&count).Error
+	return uint64(count), err
 }
 
 func (r *AccountRepository) GetTransactionCountSince(address string, since time.Time) (uint32, error) {
@@ -53,52 +166,80 @@ func (r *AccountRepository) GetTransactionCountSince(address string, since time.
 }
 
 func (r *AccountRepository) GetVolumeETHSince(address string, since time.Time) (*big.Int, error) {
-	var transactions []models.Transaction
-	err := r.db.Where("\"from\" = ? AND timestamp >= ?", address, since).Find(&transactions).Error
+	var total models.BigInt
+	err := r.db.Model(&models.Transaction{}).
+		Select("COALESCE(SUM(value), 0)").
+		Where("\"from\" = ? AND timestamp >= ? AND status = 1", address, since).
+		Row().Scan(&total)
 	if err != nil {
 		return big.NewInt(0), err
 	}
 
-	total := big.NewInt(0)
-	for _, tx := range transactions {
-		total = total.Add(total, tx.GetValue())
-	}
-
-	return total, nil
+	return total.Int(), nil
 }
 
+// GetTokenBalance возвращает баланс токена аккаунта; отсутствующий баланс считается нулевым
 func (r *AccountRepository) GetTokenBalance(address, tokenAddress string) (*models.TokenBalance, error) {
 	var balance models.TokenBalance
 
-	result := r.db.Where("address = ? AND token_address = ?", address, tokenAddress).First(&balance)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			balance = models.TokenBalance{
-				Address:      address,
-				TokenAddress: tokenAddress,
-				Balance:      "0",
-				LastUpdate:   time.Now(),
-			}
-			if err := r.db.Create(&balance).Error; err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, result.Error
-		}
+	err := r.db.Where("address = ? AND token_address = ?", address, tokenAddress).First(&balance).Error
+	if err == gorm.ErrRecordNotFound {
+		return &models.TokenBalance{
+			Address:      address,
+			TokenAddress: tokenAddress,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return &balance, nil
 }
 
-func (r *AccountRepository) UpdateTokenBalance(balance *models.TokenBalance) error {
-	balance.LastUpdate = time.Now()
-	return r.db.Save(balance).Error
+// AdjustTokenBalance атомарно изменяет баланс токена на delta и возвращает новый баланс.
+// Баланс не опускается ниже нуля: расхождения исправляет сверка с balanceOf
+func (r *AccountRepository) AdjustTokenBalance(address, tokenAddress string, delta *big.Int) (*big.Int, error) {
+	return adjustTokenBalance(r.db, address, tokenAddress, delta)
+}
+
+func adjustTokenBalance(db *gorm.DB, address, tokenAddress string, delta *big.Int) (*big.Int, error) {
+	var balance models.BigInt
+	err := db.Raw(`
+		INSERT INTO token_balances (address, token_address, balance, last_update, created_at, updated_at)
+		VALUES (?, ?, GREATEST(CAST(? AS NUMERIC), 0), NOW(), NOW(), NOW())
+		ON CONFLICT (address, token_address) DO UPDATE SET
+			balance = GREATEST(token_balances.balance + CAST(? AS NUMERIC), 0),
+			last_update = NOW(),
+			updated_at = NOW()
+		RETURNING balance
+	`, address, tokenAddress, models.NewBigInt(delta), models.NewBigInt(delta)).Row().Scan(&balance)
+	if err != nil {
+		return nil, err
+	}
+
+	return balance.Int(), nil
+}
+
+// SetTokenBalance атомарно устанавливает баланс токена аккаунта
+func (r *AccountRepository) SetTokenBalance(address, tokenAddress string, value *big.Int) error {
+	return setTokenBalance(r.db, address, tokenAddress, models.NewBigInt(value), time.Now())
+}
+
+func setTokenBalance(db *gorm.DB, address, tokenAddress string, value models.BigInt, updatedAt time.Time) error {
+	return db.Exec(`
+		INSERT INTO token_balances (address, token_address, balance, last_update, created_at, updated_at)
+		VALUES (?, ?, CAST(? AS NUMERIC), ?, NOW(), NOW())
+		ON CONFLICT (address, token_address) DO UPDATE SET
+			balance = EXCLUDED.balance,
+			last_update = EXCLUDED.last_update,
+			updated_at = NOW()
+	`, address, tokenAddress, value, updatedAt).Error
 }
 
 func (r *AccountRepository) GetUniqueTokensCount(address string) (uint32, error) {
 	var count int64
 	err := r.db.Model(&models.TokenBalance{}).
-		Where("address = ? AND balance != '0'", address).
+		Where("address = ? AND balance > 0", address).
 		Count(&count).Error
 
 	return uint32(count), err
@@ -107,7 +248,8 @@ func (r *AccountRepository) GetUniqueTokensCount(address string) (uint32, error)
 func (r *AccountRepository) GetAccountTokens(address string) ([]models.TokenBalance, error) {
 	var balances []models.TokenBalance
 
-	err := r.db.Where("address = ? AND balance != '0'", address).
+	err := r.db.Where("address = ? AND balance > 0", address).
+		Order("balance DESC").
 		Find(&balances).Error
 
 	return balances, err
@@ -116,38 +258,91 @@ func (r *AccountRepository) GetAccountTokens(address string) ([]models.TokenBala
 func (r *AccountRepository) GetTransactionsSince(since time.Time) ([]models.Transaction, error) {
 	var transactions []models.Transaction
 	// ИСПРАВЛЕНО: используем r.db вместо глобального DB
-	nextPeriod := since.Add(15 * time.Second)
+	nextPeriod := since.Add(utils.ActivityPeriod())
 	result := r.db.Where("timestamp >= ? AND timestamp < ?", since, nextPeriod).Find(&transactions)
 	return transactions, result.Error
 }
 
-func (r *AccountRepository) SaveAccountActivity(activity *models.AccountActivity) error {
-	// Пытаемся найти существующую запись
-	var existing models.AccountActivity
-	// ИСПРАВЛЕНО: используем r.db вместо глобального DB
-	result := r.db.Where("address = ? AND period = ?", activity.Address, activity.Period).First(&existing)
+// GetTransactionsInRange возвращает транзакции с временем блока в [from, to)
+func (r *AccountRepository) GetTransactionsInRange(from, to time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Where("timestamp >= ? AND timestamp < ?", from, to).Order("block_number").Find(&transactions).Error
+	return transactions, err
+}
 
-	if result.Error == nil {
-		// Обновляем существующую запись
-		existing.TransactionCount = activity.TransactionCount
-		existing.VolumeETH = activity.VolumeETH
-		existing.TokenTransfers = activity.TokenTransfers
-		return r.db.Save(&existing).Error
-	}
+// GetERC20TransfersInRange возвращает ERC20 трансферы со временем блока в [from, to)
+func (r *AccountRepository) GetERC20TransfersInRange(from, to time.Time) ([]models.ERC20Transfer, error) {
+	var transfers []models.ERC20Transfer
+	err := r.db.Where("timestamp >= ? AND timestamp < ?", from, to).Order("block_number").Find(&transfers).Error
+	return transfers, err
+}
 
-	// Создаем новую запись
-	// ИСПРАВЛЕНО: используем r.db вместо глобального DB
-	err := r.db.Create(activity).Error
-	if err != nil {
-		// Проверяем тип ошибки
-		if err.Error() == "duplicate key value violates unique constraint" {
-			logrus.Debugf("Активность для %s за период %v уже существует, пропускаем",
-				activity.Address, activity.Period)
-			return nil
+// ReplaceAccountActivities заменяет активность и контрагентов всех аккаунтов за [from, to) пересчитанными
+// и пересобирает срезы. Повторный вызов с теми же данными дает тот же результат
+func (r *AccountRepository) ReplaceAccountActivities(from, to time.Time, activities []models.AccountActivity, counterparties []models.AccountCounterparty) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("period >= ? AND period < ?", from, to).Delete(&models.AccountActivity{}).Error; err != nil {
+			return fmt.Errorf("ошибка удаления активности: %v", err)
+		}
+		if err := tx.Where("period >= ? AND period < ?", from, to).Delete(&models.AccountCounterparty{}).Error; err != nil {
+			return fmt.Errorf("ошибка удаления контрагентов: %v", err)
+		}
+
+		if len(activities) > 0 {
+			if err := tx.CreateInBatches(activities, 500).Error; err != nil {
+				return fmt.Errorf("ошибка записи активности: %v", err)
+			}
 		}
-		if err.Error() == "check constraint violation" {
-			logrus.Warnf("Попытка сохранить активность с невалидным адресом: %s",
-				activity.Address)
+		if len(counterparties) > 0 {
+			if err := tx.CreateInBatches(counterparties, 1000).Error; err != nil {
+				return fmt.Errorf("ошибка записи контрагентов: %v", err)
+			}
+		}
+
+		return refreshActivityRollups(tx, "", from, to.Add(-time.Nanosecond))
+	})
+}
+
+// SaveAccountActivity сохраняет активность аккаунта за период вместе с его контрагентами в этом периоде
+func (r *AccountRepository) SaveAccountActivity(activity *models.AccountActivity, counterparties []string) error {
+	activity.Counterparties = uint32(len(counterparties))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Атомарный upsert по (address, period) вместо чтения и последующей записи
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "address"}, {Name: "period"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"transaction_count", "volume_eth", "token_transfers", "token_volume",
+				"received_count", "volume_eth_received", "token_transfers_received", "token_volume_received",
+				"counterparties", "fees_paid", "gas_used", "gas_price_p50", "gas_price_p90", "updated_at",
+			}),
+		}).Create(activity).Error
+		if err != nil {
+			return err
+		}
+
+		// Контрагентов периода заменяем целиком
+		err = tx.Where("address = ? AND period = ?", activity.Address, activity.Period).
+			Delete(&models.AccountCounterparty{}).Error
+		if err != nil {
+			return err
+		}
+		if len(counterparties) > 0 {
+			rows := make([]models.AccountCounterparty, len(counterparties))
+			for i, counterparty := range counterparties {
+				rows[i] = models.AccountCounterparty{Address: activity.Address, Period: activity.Period, Counterparty: counterparty}
+			}
+			if err := tx.CreateInBatches(rows, 1000).Error; err != nil {
+				return err
+			}
+		}
+
+		// Пересобираем срезы, в которые попадает период
+		return refreshActivityRollups(tx, activity.Address, activity.Period, activity.Period)
+	})
+	if err != nil {
+		if IsCheckViolation(err) {
+			logrus.Warnf("Попытка сохранить активность с невалидным адресом: %s", activity.Address)
 			return nil
 		}
 		return err
@@ -162,10 +357,10 @@ func (r *AccountRepository) GetAccountActivityForPeriod(address string, period t
 	var cachedActivity models.AccountActivity
 	err := r.db.Where("address = ? AND period = ?", address, period).First(&cachedActivity).Error
 
-	// Если данные найдены в кеше и они свежие (не старше 15 секунд), возвращаем их
+	// Если данные найдены в кеше и они свежие (не старше базового периода), возвращаем их
 	if err == nil {
 		cacheAge := time.Since(cachedActivity.UpdatedAt)
-		if cacheAge < 15*time.Second {
+		if cacheAge < utils.ActivityPeriod() {
 			logrus.Debugf("Данные из кеша для %s за %v (возраст: %v)", address, period, cacheAge)
 			return &cachedActivity, nil
 		}
@@ -175,56 +370,21 @@ func (r *AccountRepository) GetAccountActivityForPeriod(address string, period t
 		return nil, err
 	}
 
-	// Кеша нет или он устарел - вычисляем из transactions
-	nextPeriod := period.Add(15 * time.Second)
-
-	var result struct {
-		TransactionCount uint32
-		VolumeETH        string
-	}
-
-	// Получаем количество исходящих транзакций и суммарный объем за период
-	err = r.db.Model(&models.Transaction{}).
-		Select(`
-			COUNT(*) as transaction_count,
-			COALESCE(SUM(CAST(value AS NUMERIC)), '0')::text as volume_eth
-		`).
-		Where("\"from\" = ? AND timestamp >= ? AND timestamp < ?",
-			address, period, nextPeriod).
-		Scan(&result).Error
-
+	// Кеша нет или он устарел - вычисляем из transactions и erc20_transfers
+	activities, counterparties, err := r.computeActivities([]string{address}, period)
 	if err != nil {
 		return nil, err
 	}
 
-	// Если нет исходящих транзакций, возвращаем nil
-	if result.TransactionCount == 0 {
+	// Если за период нет ни исходящей, ни входящей активности, возвращаем nil
+	if len(activities) == 0 {
 		return nil, gorm.ErrRecordNotFound
 	}
-
-	// Получаем количество исходящих ERC20 трансферов
-	var count int64
-	err = r.db.Model(&models.ERC20Transfer{}).
-		Where("\"from\" = ? AND created_at >= ? AND created_at < ?",
-			address, period, nextPeriod).
-		Count(&count).Error
-
-	if err != nil {
-		return nil, err
-	}
-
-	// Создаем объект активности только если есть исходящие транзакции
-	activity := &models.AccountActivity{
-		Address:          address,
-		Period:           period,
-		TransactionCount: result.TransactionCount + uint32(count),
-		VolumeETH:        result.VolumeETH,
-		TokenTransfers:   uint32(count),
-	}
+	activity := &activities[0]
 
 	// Сохраняем в кеш (асинхронно, чтобы не блокировать ответ)
 	go func() {
-		if saveErr := r.SaveAccountActivity(activity); saveErr != nil {
+		if saveErr := r.SaveAccountActivity(activity, counterparties[address]); saveErr != nil {
 			logrus.Errorf("Не удалось сохранить в кеш: %v", saveErr)
 		} else {
 			logrus.Debugf("Сохранено в кеш для %s за %v", address, period)
@@ -243,105 +403,42 @@ func (r *AccountRepository) GetAllAccountsActivityForPeriod(period time.Time) ([
 		logrus.Errorf("Ошибка чтения кеша: %v", cacheErr)
 	}
 
-	// Проверяем какие записи из кеша еще актуальны (не старше 15 сек)
+	// Проверяем какие записи из кеша еще актуальны (не старше базового периода)
 	validCached := make(map[string]models.AccountActivity)
 	for _, activity := range cachedActivities {
 		cacheAge := time.Since(activity.UpdatedAt)
-		if cacheAge < 15*time.Second {
+		if cacheAge < utils.ActivityPeriod() {
 			validCached[activity.Address] = activity
 		}
 	}
 
 	logrus.Debugf("Найдено %d актуальных записей в кеше для периода %v", len(validCached), period)
 
-	// Получаем всех активных отправителей за период из transactions
-	nextPeriod := period.Add(15 * time.Second)
-
-	var allActiveAddresses []string
-	err := r.db.Model(&models.Transaction{}).
-		Select("DISTINCT \"from\"").
-		Where("timestamp >= ? AND timestamp < ?", period, nextPeriod).
-		Pluck("\"from\"", &allActiveAddresses).Error
-
+	// Считаем активность всех участников периода и берем пересчитанные данные для аккаунтов без актуального кеша
+	computed, counterparties, err := r.computeActivities(nil, period)
 	if err != nil {
 		return nil, err
 	}
 
-	logrus.Debugf("Всего активных отправителей в период: %d", len(allActiveAddresses))
-
-	// Определяем какие аккаунты нужно пересчитать
-	var addressesToCalculate []string
-	for _, address := range allActiveAddresses {
-		if _, exists := validCached[address]; !exists {
-			addressesToCalculate = append(addressesToCalculate, address)
-		}
-	}
-
-	logrus.Debugf("Нужно пересчитать %d аккаунтов", len(addressesToCalculate))
-
-	// Пересчитываем недостающие аккаунты
-	if len(addressesToCalculate) > 0 {
-		var results []struct {
-			Address          string
-			TransactionCount uint32
-			VolumeETH        string
-			TokenTransfers   uint32
-		}
-
-		// Получаем статистику по обычным транзакциям
-		err = r.db.Model(&models.Transaction{}).
-			Select(`
-				"from" as address,
-				COUNT(*) as transaction_count,
-				COALESCE(SUM(CAST(value AS NUMERIC)), '0')::text as volume_eth
-			`).
-			Where("\"from\" IN ? AND timestamp >= ? AND timestamp < ?",
-				addressesToCalculate, period, nextPeriod).
-			Group("\"from\"").
-			Scan(&results).Error
-
-		if err != nil {
-			return nil, err
+	recalculated := 0
+	for _, activity := range computed {
+		if _, exists := validCached[activity.Address]; exists {
+			continue
 		}
 
-		// Для каждого адреса получаем количество ERC20 трансферов
-		for i := range results {
-			var tokenCount int64
-			err = r.db.Model(&models.ERC20Transfer{}).
-				Where("\"from\" = ? AND created_at >= ? AND created_at < ?",
-					results[i].Address, period, nextPeriod).
-				Count(&tokenCount).Error
-
-			if err != nil {
-				logrus.Errorf("Ошибка подсчета ERC20 трансферов для %s: %v", results[i].Address, err)
-				continue
-			}
-
-			results[i].TokenTransfers = uint32(tokenCount)
-			results[i].TransactionCount += uint32(tokenCount)
-		}
+		validCached[activity.Address] = activity
+		recalculated++
 
-		// Добавляем пересчитанные данные в validCached и сохраняем в кеш
-		for _, result := range results {
-			activity := models.AccountActivity{
-				Address:          result.Address,
-				Period:           period,
-				TransactionCount: result.TransactionCount,
-				VolumeETH:        result.VolumeETH,
-				TokenTransfers:   result.TokenTransfers,
+		// Сохраняем в кеш асинхронно
+		go func(act models.AccountActivity) {
+			if saveErr := r.SaveAccountActivity(&act, counterparties[act.Address]); saveErr != nil {
+				logrus.Errorf("Не удалось сохранить в кеш %s: %v", act.Address, saveErr)
 			}
-
-			validCached[result.Address] = activity
-
-			// Сохраняем в кеш асинхронно
-			go func(act models.AccountActivity) {
-				if saveErr := r.SaveAccountActivity(&act); saveErr != nil {
-					logrus.Errorf("Не удалось сохранить в кеш %s: %v", act.Address, saveErr)
-				}
-			}(activity)
-		}
+		}(activity)
 	}
 
+	logrus.Debugf("Пересчитано %d аккаунтов", recalculated)
+
 	// Преобразуем в slice
 	activities := make([]models.AccountActivity, 0, len(validCached))
 	for _, activity := range validCached {
@@ -352,23 +449,109 @@ func (r *AccountRepository) GetAllAccountsActivityForPeriod(period time.Time) ([
 	return activities, nil
 }
 
+// activityFlowsSQL разворачивает ETH транзакции и ERC20 трансферы периода в потоки с точки зрения каждого участника:
+// исходящие у отправителя и входящие у получателя. Mint не считается исходящим, burn - входящим.
+// Revert транзакции дают комиссию и газ, но не объем
+const activityFlowsSQL = `
+	WITH flows AS (
+		SELECT "from" AS address, "to" AS counterparty, FALSE AS token, TRUE AS outgoing,
+			CASE WHEN status = 1 THEN value ELSE 0 END AS value, gas_used, gas_price
+		FROM transactions WHERE timestamp >= @from AND timestamp < @to
+		UNION ALL
+		SELECT "to", "from", FALSE, FALSE, CASE WHEN status = 1 THEN value ELSE 0 END, NULL, NULL
+		FROM transactions WHERE timestamp >= @from AND timestamp < @to AND "to" != ''
+		UNION ALL
+		SELECT "from", "to", TRUE, TRUE, value, NULL, NULL
+		FROM erc20_transfers WHERE timestamp >= @from AND timestamp < @to AND "from" != @zero
+		UNION ALL
+		SELECT "to", "from", TRUE, FALSE, value, NULL, NULL
+		FROM erc20_transfers WHERE timestamp >= @from AND timestamp < @to AND "to" != @zero
+	)`
+
+// computeActivities считает исходящую и входящую активность и контрагентов за период из transactions и erc20_transfers.
+// addresses == nil - для всех участников периода
+func (r *AccountRepository) computeActivities(addresses []string, period time.Time) ([]models.AccountActivity, map[string][]string, error) {
+	params := map[string]interface{}{
+		"from":      period,
+		"to":        period.Add(utils.ActivityPeriod()),
+		"zero":      models.ZeroAddress,
+		"addresses": addresses,
+	}
+	filter := "TRUE"
+	if addresses != nil {
+		filter = "address IN @addresses"
+	}
+
+	var activities []models.AccountActivity
+	err := r.db.Raw(activityFlowsSQL+`
+		SELECT address,
+			COUNT(*) FILTER (WHERE outgoing) AS transaction_count,
+			COALESCE(SUM(value) FILTER (WHERE outgoing AND NOT token), 0) AS volume_eth,
+			COUNT(*) FILTER (WHERE outgoing AND token) AS token_transfers,
+			COALESCE(SUM(value) FILTER (WHERE outgoing AND token), 0) AS token_volume,
+			COUNT(*) FILTER (WHERE NOT outgoing AND NOT token) AS received_count,
+			COALESCE(SUM(value) FILTER (WHERE NOT outgoing AND NOT token), 0) AS volume_eth_received,
+			COUNT(*) FILTER (WHERE NOT outgoing AND token) AS token_transfers_received,
+			COALESCE(SUM(value) FILTER (WHERE NOT outgoing AND token), 0) AS token_volume_received,
+			COALESCE(SUM(gas_used * gas_price), 0) AS fees_paid,
+			COALESCE(SUM(gas_used), 0) AS gas_used,
+			COALESCE(percentile_disc(0.5) WITHIN GROUP (ORDER BY gas_price), 0) AS gas_price_p50,
+			COALESCE(percentile_disc(0.9) WITHIN GROUP (ORDER BY gas_price), 0) AS gas_price_p90
+		FROM flows
+		WHERE `+filter+`
+		GROUP BY address
+	`, params).Scan(&activities).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pairs []struct {
+		Address      string
+		Counterparty string
+	}
+	err = r.db.Raw(activityFlowsSQL+`
+		SELECT DISTINCT address, counterparty
+		FROM flows
+		WHERE `+filter+` AND counterparty != @zero AND counterparty != ''
+	`, params).Scan(&pairs).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counterparties := make(map[string][]string)
+	for _, pair := range pairs {
+		counterparties[pair.Address] = append(counterparties[pair.Address], pair.Counterparty)
+	}
+
+	for i := range activities {
+		activities[i].Period = period
+		activities[i].Counterparties = uint32(len(counterparties[activities[i].Address]))
+	}
+
+	return activities, counterparties, nil
+}
+
 // GetAccountActivityHistory получает историю активности аккаунта за несколько периодов
 func (r *AccountRepository) GetAccountActivityHistory(address string, fromPeriod, toPeriod time.Time) ([]models.AccountActivity, error) {
 	var results []struct {
-		Period           time.Time
-		TransactionCount uint32
-		VolumeETH        string
+		Period            time.Time
+		TransactionCount  uint32
+		VolumeETH         models.BigInt
+		ReceivedCount     uint32
+		VolumeETHReceived models.BigInt
 	}
 
-	// Группируем по 15-секундным периодам
+	// Группируем по базовым периодам
+	seconds := utils.ActivityPeriod().Seconds()
 	err := r.db.Model(&models.Transaction{}).
 		Select(`
-			date_trunc('minute', timestamp) + 
-			INTERVAL '15 seconds' * FLOOR(EXTRACT(SECOND FROM timestamp) / 15) as period,
-			COUNT(*) as transaction_count,
-			COALESCE(SUM(CAST(value AS NUMERIC)), 0)::text as volume_eth
-		`).
-		Where("\"from\" = ? AND timestamp >= ? AND timestamp < ?", address, fromPeriod, toPeriod).
+			to_timestamp(FLOOR(EXTRACT(EPOCH FROM timestamp) / ?) * ?) as period,
+			COUNT(*) FILTER (WHERE "from" = ?) as transaction_count,
+			COALESCE(SUM(value) FILTER (WHERE "from" = ? AND status = 1), 0) as volume_eth,
+			COUNT(*) FILTER (WHERE "to" = ?) as received_count,
+			COALESCE(SUM(value) FILTER (WHERE "to" = ? AND status = 1), 0) as volume_eth_received
+		`, seconds, seconds, address, address, address, address).
+		Where("(\"from\" = ? OR \"to\" = ?) AND timestamp >= ? AND timestamp < ?", address, address, fromPeriod, toPeriod).
 		Group("period").
 		Order("period").
 		Scan(&results).Error
@@ -386,6 +569,9 @@ func (r *AccountRepository) GetAccountActivityHistory(address string, fromPeriod
 			TransactionCount: result.TransactionCount,
 			VolumeETH:        result.VolumeETH,
 			TokenTransfers:   0,
+
+			ReceivedCount:     result.ReceivedCount,
+			VolumeETHReceived: result.VolumeETHReceived,
 		}
 	}
 
@@ -403,3 +589,252 @@ func (r *AccountRepository) GetActivitiesAfterIDWithLimit(lastID uint, limit int
 	err := r.db.Where("id > ?", lastID).Order("id ASC").Limit(limit).Find(&activities).Error
 	return activities, err
 }
+
+// TokenVolume - объем токена аккаунта за период
+type TokenVolume struct {
+	TokenAddress  string
+	TransferCount uint64
+	Sent          *big.Int
+	Received      *big.Int
+	Net           *big.Int // полученный минус отправленный
+}
+
+// GetTokenVolume считает отправленный, полученный и чистый объем токена аккаунта за период [from, to)
+func (r *AccountRepository) GetTokenVolume(address, tokenAddress string, from, to time.Time) (*TokenVolume, error) {
+	volumes, err := r.queryTokenVolumes(address, from, to, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(volumes) == 0 {
+		return &TokenVolume{
+			TokenAddress: tokenAddress,
+			Sent:         big.NewInt(0),
+			Received:     big.NewInt(0),
+			Net:          big.NewInt(0),
+		}, nil
+	}
+	return &volumes[0], nil
+}
+
+// GetTokenVolumes считает объемы аккаунта за период [from, to) по каждому токену
+func (r *AccountRepository) GetTokenVolumes(address string, from, to time.Time) ([]TokenVolume, error) {
+	return r.queryTokenVolumes(address, from, to, "")
+}
+
+func (r *AccountRepository) queryTokenVolumes(address string, from, to time.Time, tokenAddress string) ([]TokenVolume, error) {
+	var rows []struct {
+		ContractAddress string
+		TransferCount   uint64
+		Sent            models.BigInt
+		Received        models.BigInt
+	}
+
+	query := r.db.Model(&models.ERC20Transfer{}).
+		Select(`
+			contract_address,
+			COUNT(*) as transfer_count,
+			COALESCE(SUM(CASE WHEN "from" = ? THEN value ELSE 0 END), 0) as sent,
+			COALESCE(SUM(CASE WHEN "to" = ? THEN value ELSE 0 END), 0) as received
+		`, address, address).
+		Where("(\"from\" = ? OR \"to\" = ?) AND timestamp >= ? AND timestamp < ?", address, address, from, to)
+	if tokenAddress != "" {
+		query = query.Where("contract_address = ?", tokenAddress)
+	}
+
+	if err := query.Group("contract_address").Order("contract_address").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	volumes := make([]TokenVolume, len(rows))
+	for i, row := range rows {
+		sent := row.Sent.Int()
+		received := row.Received.Int()
+		volumes[i] = TokenVolume{
+			TokenAddress:  row.ContractAddress,
+			TransferCount: row.TransferCount,
+			Sent:          sent,
+			Received:      received,
+			Net:           new(big.Int).Sub(received, sent),
+		}
+	}
+
+	return volumes, nil
+}
+
+// maxActivityPoints - максимум точек ряда, при котором еще используется более мелкое разрешение
+const maxActivityPoints = 1000
+
+// rollupSources описывает сборку среза: разрешение, единица date_trunc и источник (предыдущее разрешение)
+var rollupSources = []struct {
+	resolution string
+	unit       string
+	source     string
+}{
+	{models.ActivityResolutionMinute, "minute", models.ActivityResolutionBase},
+	{models.ActivityResolutionHour, "hour", models.ActivityResolutionMinute},
+	{models.ActivityResolutionDay, "day", models.ActivityResolutionHour},
+}
+
+// refreshActivityRollups пересобирает срезы 1m, 1h и 1d, покрывающие моменты [from, to], каждый из предыдущего.
+// Пустой address - для всех аккаунтов. Пересборка идемпотентна: срезы без исходных данных удаляются
+func refreshActivityRollups(tx *gorm.DB, address string, from, to time.Time) error {
+	for _, rollup := range rollupSources {
+		err := tx.Exec(fmt.Sprintf(`
+			DELETE FROM account_activity_rollups
+			WHERE resolution = ? AND (? = '' OR address = ?)
+				AND period >= date_trunc('%[1]s', ?::timestamptz)
+				AND period < date_trunc('%[1]s', ?::timestamptz) + INTERVAL '1 %[1]s'
+		`, rollup.unit), rollup.resolution, address, address, from, to).Error
+		if err != nil {
+			return fmt.Errorf("ошибка очистки среза %s: %v", rollup.resolution, err)
+		}
+
+		sourceTable, sourceFilter := "account_activity_rollups", "resolution = '"+rollup.source+"'"
+		if rollup.source == models.ActivityResolutionBase {
+			sourceTable, sourceFilter = "account_activities", "TRUE"
+		}
+
+		err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO account_activity_rollups
+				(address, resolution, period, transaction_count, volume_eth, token_transfers, token_volume,
+				received_count, volume_eth_received, token_transfers_received, token_volume_received,
+				fees_paid, gas_used, updated_at)
+			SELECT address, ?, date_trunc('%[1]s', period),
+				SUM(transaction_count), SUM(volume_eth), SUM(token_transfers), SUM(token_volume),
+				SUM(received_count), SUM(volume_eth_received), SUM(token_transfers_received), SUM(token_volume_received),
+				SUM(fees_paid), SUM(gas_used), NOW()
+			FROM %[2]s
+			WHERE %[3]s AND (? = '' OR address = ?)
+				AND period >= date_trunc('%[1]s', ?::timestamptz)
+				AND period < date_trunc('%[1]s', ?::timestamptz) + INTERVAL '1 %[1]s'
+			GROUP BY address, date_trunc('%[1]s', period)
+			ON CONFLICT (address, resolution, period) DO UPDATE SET
+				transaction_count = EXCLUDED.transaction_count,
+				volume_eth = EXCLUDED.volume_eth,
+				token_transfers = EXCLUDED.token_transfers,
+				token_volume = EXCLUDED.token_volume,
+				received_count = EXCLUDED.received_count,
+				volume_eth_received = EXCLUDED.volume_eth_received,
+				token_transfers_received = EXCLUDED.token_transfers_received,
+				token_volume_received = EXCLUDED.token_volume_received,
+				fees_paid = EXCLUDED.fees_paid,
+				gas_used = EXCLUDED.gas_used,
+				updated_at = NOW()
+		`, rollup.unit, sourceTable, sourceFilter),
+			rollup.resolution, address, address, from, to).Error
+		if err != nil {
+			return fmt.Errorf("ошибка сборки среза %s: %v", rollup.resolution, err)
+		}
+
+		// Различных контрагентов нельзя сложить из более мелких срезов - считаем по базовым периодам
+		err = tx.Exec(fmt.Sprintf(`
+			UPDATE account_activity_rollups r SET counterparties = c.count
+			FROM (
+				SELECT address, date_trunc('%[1]s', period) AS bucket, COUNT(DISTINCT counterparty) AS count
+				FROM account_counterparties
+				WHERE (? = '' OR address = ?)
+					AND period >= date_trunc('%[1]s', ?::timestamptz)
+					AND period < date_trunc('%[1]s', ?::timestamptz) + INTERVAL '1 %[1]s'
+				GROUP BY address, date_trunc('%[1]s', period)
+			) c
+			WHERE r.resolution = ? AND r.address = c.address AND r.period = c.bucket
+		`, rollup.unit), address, address, from, to, rollup.resolution).Error
+		if err != nil {
+			return fmt.Errorf("ошибка подсчета контрагентов среза %s: %v", rollup.resolution, err)
+		}
+
+		// Перцентили цены газа тоже не складываются - считаем по транзакциям среза
+		err = tx.Exec(fmt.Sprintf(`
+			UPDATE account_activity_rollups r SET gas_price_p50 = g.p50, gas_price_p90 = g.p90
+			FROM (
+				SELECT "from" AS address, date_trunc('%[1]s', timestamp) AS bucket,
+					percentile_disc(0.5) WITHIN GROUP (ORDER BY gas_price) AS p50,
+					percentile_disc(0.9) WITHIN GROUP (ORDER BY gas_price) AS p90
+				FROM transactions
+				WHERE (? = '' OR "from" = ?)
+					AND timestamp >= date_trunc('%[1]s', ?::timestamptz)
+					AND timestamp < date_trunc('%[1]s', ?::timestamptz) + INTERVAL '1 %[1]s'
+				GROUP BY "from", date_trunc('%[1]s', timestamp)
+			) g
+			WHERE r.resolution = ? AND r.address = g.address AND r.period = g.bucket
+		`, rollup.unit), address, address, from, to, rollup.resolution).Error
+		if err != nil {
+			return fmt.Errorf("ошибка подсчета цены газа среза %s: %v", rollup.resolution, err)
+		}
+	}
+	return nil
+}
+
+// RebuildActivityRollups пересобирает срезы активности за период [from, to]; пустой address - для всех аккаунтов
+func (r *AccountRepository) RebuildActivityRollups(address string, from, to time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return refreshActivityRollups(tx, address, from, to)
+	})
+}
+
+// ChooseActivityResolution выбирает самое мелкое разрешение, при котором ряд за [from, to) не длиннее maxActivityPoints
+func ChooseActivityResolution(from, to time.Time) string {
+	span := to.Sub(from)
+	for _, resolution := range []string{models.ActivityResolutionBase, models.ActivityResolutionMinute, models.ActivityResolutionHour} {
+		if span <= models.ResolutionDuration(resolution)*maxActivityPoints {
+			return resolution
+		}
+	}
+	return models.ActivityResolutionDay
+}
+
+// GetActivityRollups возвращает активность аккаунта за [from, to) в указанном разрешении, в хронологическом порядке.
+// Для базового разрешения данные берутся из account_activities
+func (r *AccountRepository) GetActivityRollups(address, resolution string, from, to time.Time) ([]models.AccountActivityRollup, error) {
+	var rollups []models.AccountActivityRollup
+
+	if resolution == models.ActivityResolutionBase {
+		var activities []models.AccountActivity
+		err := r.db.Where("address = ? AND period >= ? AND period < ?", address, from, to).
+			Order("period").
+			Find(&activities).Error
+		if err != nil {
+			return nil, err
+		}
+
+		rollups = make([]models.AccountActivityRollup, len(activities))
+		for i, activity := range activities {
+			rollups[i] = models.AccountActivityRollup{
+				Address:          activity.Address,
+				Resolution:       models.ActivityResolutionBase,
+				Period:           activity.Period,
+				TransactionCount: activity.TransactionCount,
+				VolumeETH:        activity.VolumeETH,
+				TokenTransfers:   activity.TokenTransfers,
+				TokenVolume:      activity.TokenVolume,
+
+				ReceivedCount:          activity.ReceivedCount,
+				VolumeETHReceived:      activity.VolumeETHReceived,
+				TokenTransfersReceived: activity.TokenTransfersReceived,
+				TokenVolumeReceived:    activity.TokenVolumeReceived,
+				Counterparties:         activity.Counterparties,
+
+				FeesPaid:    activity.FeesPaid,
+				GasUsed:     activity.GasUsed,
+				GasPriceP50: activity.GasPriceP50,
+				GasPriceP90: activity.GasPriceP90,
+				UpdatedAt:   activity.UpdatedAt,
+			}
+		}
+		return rollups, nil
+	}
+
+	// Срез, начавшийся до from, целиком попадает в результат, чтобы не терять его начало
+	err := r.db.Where("address = ? AND resolution = ? AND period > ? AND period < ?",
+		address, resolution, from.Add(-models.ResolutionDuration(resolution)), to).
+		Order("period").
+		Find(&rollups).Error
+	return rollups, err
+}
+
+// GetActivitySeries возвращает активность аккаунта за [from, to) в разрешении, подобранном по длине периода
+func (r *AccountRepository) GetActivitySeries(address string, from, to time.Time) (string, []models.AccountActivityRollup, error) {
+	resolution := ChooseActivityResolution(from, to)
+	rollups, err := r.GetActivityRollups(address, resolution, from, to)
+	return resolution, rollups, err
+}
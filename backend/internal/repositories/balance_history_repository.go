@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BalanceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewBalanceHistoryRepository() *BalanceHistoryRepository {
+	return &BalanceHistoryRepository{db: DB}
+}
+
+// RecordChanges сохраняет изменения баланса и пересчитывает итоговые балансы леджера.
+// Повторная запись того же изменения игнорируется, поэтому вызов идемпотентен
+func (r *BalanceHistoryRepository) RecordChanges(changes []models.BalanceChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&changes).Error; err != nil {
+			return err
+		}
+
+		// Изменения могут прийти не по порядку блоков - пересчитываем хвост леджера с самого раннего блока
+		type ledgerKey struct{ address, token string }
+		fromBlocks := make(map[ledgerKey]uint64)
+		for _, change := range changes {
+			key := ledgerKey{change.Address, change.TokenAddress}
+			if block, exists := fromBlocks[key]; !exists || change.BlockNumber < block {
+				fromBlocks[key] = change.BlockNumber
+			}
+		}
+
+		for key, fromBlock := range fromBlocks {
+			if err := recomputeBalances(tx, key.address, key.token, fromBlock); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// recomputeBalances пересчитывает накопленный баланс записей леджера начиная с блока
+func recomputeBalances(tx *gorm.DB, address, tokenAddress string, fromBlock uint64) error {
+	return tx.Exec(`
+		UPDATE balance_changes AS bc SET balance = running.balance
+		FROM (
+			SELECT id, SUM(delta) OVER (ORDER BY block_number, tx_index, log_index, id) AS balance
+			FROM balance_changes
+			WHERE address = ? AND token_address = ?
+		) AS running
+		WHERE bc.id = running.id AND bc.block_number >= ? AND bc.balance IS DISTINCT FROM running.balance
+	`, address, tokenAddress, fromBlock).Error
+}
+
+// HasHistory проверяет, есть ли в леджере записи по адресу и токену
+func (r *BalanceHistoryRepository) HasHistory(address, tokenAddress string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.BalanceChange{}).
+		Where("address = ? AND token_address = ?", address, tokenAddress).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetBalanceAtBlock возвращает баланс на конец блока (аналог AnalyzerToken.getBalanceAtBlock)
+func (r *BalanceHistoryRepository) GetBalanceAtBlock(address, tokenAddress string, block uint64) (*big.Int, error) {
+	var change models.BalanceChange
+	err := r.db.Where("address = ? AND token_address = ? AND block_number <= ?", address, tokenAddress, block).
+		Order("block_number DESC, tx_index DESC, log_index DESC, id DESC").
+		First(&change).Error
+	if err == gorm.ErrRecordNotFound {
+		return big.NewInt(0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return change.Balance.Int(), nil
+}
+
+// GetLatestBalance возвращает последний баланс по леджеру; found = false, если записей нет
+func (r *BalanceHistoryRepository) GetLatestBalance(address, tokenAddress string) (balance *big.Int, found bool, err error) {
+	var change models.BalanceChange
+	err = r.db.Where("address = ? AND token_address = ?", address, tokenAddress).
+		Order("block_number DESC, tx_index DESC, log_index DESC, id DESC").
+		First(&change).Error
+	if err == gorm.ErrRecordNotFound {
+		return big.NewInt(0), false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return change.Balance.Int(), true, nil
+}
+
+// GetAddressesChangedSince возвращает адреса, баланс которых менялся в блоках (sinceBlock, toBlock]
+func (r *BalanceHistoryRepository) GetAddressesChangedSince(tokenAddress string, sinceBlock, toBlock uint64) ([]string, error) {
+	var addresses []string
+	err := r.db.Model(&models.BalanceChange{}).
+		Where("token_address = ? AND block_number > ? AND block_number <= ?", tokenAddress, sinceBlock, toBlock).
+		Distinct("address").
+		Pluck("address", &addresses).Error
+	return addresses, err
+}
+
+// GetBalanceAtTime возвращает баланс на момент времени
+func (r *BalanceHistoryRepository) GetBalanceAtTime(address, tokenAddress string, at time.Time) (*big.Int, error) {
+	var change models.BalanceChange
+	err := r.db.Where("address = ? AND token_address = ? AND timestamp <= ?", address, tokenAddress, at).
+		Order("block_number DESC, tx_index DESC, log_index DESC, id DESC").
+		First(&change).Error
+	if err == gorm.ErrRecordNotFound {
+		return big.NewInt(0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return change.Balance.Int(), nil
+}
+
+// GetBalanceSeries возвращает изменения баланса за период в хронологическом порядке (границы включительно)
+func (r *BalanceHistoryRepository) GetBalanceSeries(address, tokenAddress string, from, to time.Time) ([]models.BalanceChange, error) {
+	var changes []models.BalanceChange
+	err := r.db.Where("address = ? AND token_address = ? AND timestamp >= ? AND timestamp <= ?", address, tokenAddress, from, to).
+		Order("block_number, tx_index, log_index, id").
+		Find(&changes).Error
+	return changes, err
+}
+
+// GetBalanceSeriesByBlocks возвращает изменения баланса в диапазоне блоков (границы включительно)
+func (r *BalanceHistoryRepository) GetBalanceSeriesByBlocks(address, tokenAddress string, fromBlock, toBlock uint64) ([]models.BalanceChange, error) {
+	var changes []models.BalanceChange
+	err := r.db.Where("address = ? AND token_address = ? AND block_number >= ? AND block_number <= ?", address, tokenAddress, fromBlock, toBlock).
+		Order("block_number, tx_index, log_index, id").
+		Find(&changes).Error
+	return changes, err
+}
@@ -41,15 +41,65 @@ func Migrate() error {
 		return fmt.Errorf("соединение с базой данных не установлено")
 	}
 
-	err := DB.AutoMigrate(
+	if err := dropZeroAddressChecks(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := removeDuplicatesForUniqueIndexes(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := addTransferTimestamps(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := addContractCallFlag(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := addTransferLogIndex(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := addTransferAccountedFlag(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := addTransferRecordedFlag(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := allowContractCreations(); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	tables := []interface{}{
 		&models.Transaction{},
 		&models.ERC20Transfer{},
 		&models.AnalyzerState{},
+		&models.ListenerCheckpoint{},
 		&models.AccountStats{},
 		&models.AccountActivity{},
+		&models.AccountActivityRollup{},
+		&models.AccountCounterparty{},
 		&models.TokenBalance{},
-	)
-	if err != nil {
+		&models.Token{},
+		&models.ExternalTransactionRelay{},
+		&models.StatsDiscrepancy{},
+		&models.BalanceCorrection{},
+		&models.MethodSignature{},
+		&models.TotalSupplySnapshot{},
+		&models.BalanceChange{},
+		&models.HolderDistributionSnapshot{},
+		&models.HolderDistributionEntry{},
+		&models.AddressEdge{},
+	}
+
+	if err := convertAmountColumns(tables); err != nil {
+		return fmt.Errorf("ошибка миграции: %w", err)
+	}
+
+	if err := DB.AutoMigrate(tables...); err != nil {
 		return fmt.Errorf("ошибка миграции: %w", err)
 	}
 
@@ -57,6 +107,308 @@ func Migrate() error {
 	return nil
 }
 
+// dropZeroAddressChecks удаляет старые ограничения erc20_transfers, запрещавшие нулевой адрес,
+// чтобы хранить mint и burn. AutoMigrate затем создает ограничения заново без этого условия
+func dropZeroAddressChecks() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.ERC20Transfer{}) {
+		return nil
+	}
+
+	var names []string
+	err := DB.Raw(`
+		SELECT conname FROM pg_constraint
+		WHERE conrelid = 'erc20_transfers'::regclass AND contype = 'c'
+			AND pg_get_constraintdef(oid) LIKE ?
+	`, "%"+models.ZeroAddress+"%").Scan(&names).Error
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := migrator.DropConstraint(&models.ERC20Transfer{}, name); err != nil {
+			return err
+		}
+		logrus.Infof("Удалено ограничение %s, запрещавшее mint/burn трансферы", name)
+	}
+
+	return nil
+}
+
+// allowContractCreations удаляет старое ограничение transactions, запрещавшее пустой "to": из-за него транзакции
+// деплоя не сохранялись. AutoMigrate создаст ограничение заново без этого условия
+func allowContractCreations() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Transaction{}) {
+		return nil
+	}
+
+	var names []string
+	err := DB.Raw(`
+		SELECT conname FROM pg_constraint
+		WHERE conrelid = 'transactions'::regclass AND contype = 'c'
+			AND pg_get_constraintdef(oid) LIKE ?
+	`, `%"to" <> ''%`).Scan(&names).Error
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := migrator.DropConstraint(&models.Transaction{}, name); err != nil {
+			return err
+		}
+		logrus.Infof("Удалено ограничение %s, запрещавшее транзакции деплоя", name)
+	}
+
+	return nil
+}
+
+// addTransferTimestamps добавляет в erc20_transfers время блока и заполняет его для уже сохраненных трансферов:
+// из транзакции, а если она не проиндексирована - временем записи. Индекс по (block_number, created_at) удаляется,
+// так как все запросы по времени перешли на timestamp
+func addTransferTimestamps() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.ERC20Transfer{}) || migrator.HasColumn(&models.ERC20Transfer{}, "timestamp") {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE erc20_transfers ADD COLUMN timestamp timestamptz`).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			UPDATE erc20_transfers e SET timestamp = t.timestamp
+			FROM transactions t WHERE t.hash = e.transaction_hash
+		`)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		fallback := tx.Exec(`UPDATE erc20_transfers SET timestamp = created_at WHERE timestamp IS NULL`)
+		if fallback.Error != nil {
+			return fallback.Error
+		}
+
+		if err := tx.Exec(`ALTER TABLE erc20_transfers ALTER COLUMN timestamp SET NOT NULL`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`DROP INDEX IF EXISTS idx_erc20_block_time`).Error; err != nil {
+			return err
+		}
+
+		logrus.Infof("Время блока заполнено для %d ERC20 трансферов (для %d взято время записи)",
+			result.RowsAffected+fallback.RowsAffected, fallback.RowsAffected)
+		return nil
+	})
+}
+
+// addContractCallFlag добавляет в transactions признак вызова контракта. Calldata старых транзакций не сохранялась,
+// поэтому для них признак восстанавливается по расходу газа: простой перевод ETH тратит ровно 21000
+func addContractCallFlag() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Transaction{}) || migrator.HasColumn(&models.Transaction{}, "contract_call") {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE transactions ADD COLUMN contract_call boolean NOT NULL DEFAULT false`).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`UPDATE transactions SET contract_call = TRUE WHERE gas_used > 21000`)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		logrus.Infof("Признак вызова контракта восстановлен для %d транзакций", result.RowsAffected)
+		return nil
+	})
+}
+
+// addTransferLogIndex добавляет в erc20_transfers индекс лога и снимает уникальность с transaction_hash,
+// из-за которой сохранялся только первый Transfer транзакции. Индекс лога уже сохраненных трансферов
+// восстанавливается по леджеру балансов (запись получателя, а для burn - отправителя, с той же суммой),
+// остальным остается 0 - до миграции хеш транзакции был уникален, поэтому конфликтов нет
+func addTransferLogIndex() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.ERC20Transfer{}) || migrator.HasColumn(&models.ERC20Transfer{}, "log_index") {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE erc20_transfers ADD COLUMN log_index bigint NOT NULL DEFAULT 0`).Error; err != nil {
+			return err
+		}
+
+		var restored int64
+		if migrator.HasTable(&models.BalanceChange{}) {
+			result := tx.Exec(`
+				UPDATE erc20_transfers e SET log_index = b.log_index
+				FROM (
+					SELECT transaction_hash, token_address, address, ABS(delta) AS amount, MIN(log_index) AS log_index
+					FROM balance_changes WHERE log_index >= 0
+					GROUP BY transaction_hash, token_address, address, ABS(delta)
+				) b
+				WHERE b.transaction_hash = e.transaction_hash AND b.token_address = e.contract_address
+					AND b.address = CASE WHEN e."to" = ? THEN e."from" ELSE e."to" END
+					AND b.amount = e.value
+			`, models.ZeroAddress)
+			if result.Error != nil {
+				return result.Error
+			}
+			restored = result.RowsAffected
+		}
+
+		if err := tx.Exec(`DROP INDEX IF EXISTS idx_erc20_transfers_transaction_hash`).Error; err != nil {
+			return err
+		}
+
+		logrus.Infof("Индекс лога восстановлен по леджеру для %d ERC20 трансферов", restored)
+		return nil
+	})
+}
+
+// addTransferAccountedFlag добавляет в erc20_transfers признак учета трансфера анализатором.
+// Какие из уже сохраненных трансферов были учтены, не восстановить, поэтому все они считаются учтенными -
+// иначе повторная обработка блоков учла бы их второй раз
+func addTransferAccountedFlag() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.ERC20Transfer{}) || migrator.HasColumn(&models.ERC20Transfer{}, "accounted") {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE erc20_transfers ADD COLUMN accounted boolean NOT NULL DEFAULT true`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`ALTER TABLE erc20_transfers ALTER COLUMN accounted SET DEFAULT false`).Error
+	})
+}
+
+// addTransferRecordedFlag добавляет в erc20_transfers признак того, что перевод учтен в статистике AnalyzerToken.
+// Логи уже сохраненных трансферов не хранятся, поэтому для них признак восстанавливается по виду: переводы
+// считаются учтенными, mint и burn - нет (переводы через transferFrom отличить уже нельзя)
+func addTransferRecordedFlag() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.ERC20Transfer{}) || migrator.HasColumn(&models.ERC20Transfer{}, "recorded") {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE erc20_transfers ADD COLUMN recorded boolean NOT NULL DEFAULT false`).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`UPDATE erc20_transfers SET recorded = TRUE WHERE kind = ?`, models.TransferKindTransfer)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		logrus.Infof("Признак учета в статистике контракта восстановлен для %d ERC20 трансферов", result.RowsAffected)
+		return nil
+	})
+}
+
+// removeDuplicatesForUniqueIndexes удаляет дубли, накопившиеся до появления уникальных индексов,
+// иначе AutoMigrate не сможет их создать
+func removeDuplicatesForUniqueIndexes() error {
+	migrator := DB.Migrator()
+
+	if migrator.HasTable(&models.Transaction{}) && !migrator.HasIndex(&models.Transaction{}, "idx_transactions_hash") {
+		result := DB.Exec(`
+			DELETE FROM transactions a USING transactions b
+			WHERE a.hash = b.hash AND a.id > b.id
+		`)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			logrus.Warnf("Удалено %d дублей транзакций", result.RowsAffected)
+		}
+	}
+
+	if migrator.HasTable(&models.TokenBalance{}) && !migrator.HasIndex(&models.TokenBalance{}, "idx_token_balances_address_token") {
+		// Оставляем самую свежую запись баланса
+		result := DB.Exec(`
+			DELETE FROM token_balances a USING token_balances b
+			WHERE a.address = b.address AND a.token_address = b.token_address
+				AND (COALESCE(a.updated_at, 'epoch'), a.id) < (COALESCE(b.updated_at, 'epoch'), b.id)
+		`)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			logrus.Warnf("Удалено %d дублей балансов токенов", result.RowsAffected)
+		}
+	}
+
+	return nil
+}
+
+// convertAmountColumns переводит колонки сумм (text, varchar, numeric без точности) в numeric(78,0).
+// AutoMigrate не меняет numeric на numeric(78,0) и не умеет разбирать пустые строки старых text-колонок
+func convertAmountColumns(tables []interface{}) error {
+	migrator := DB.Migrator()
+
+	for _, table := range tables {
+		if !migrator.HasTable(table) {
+			continue
+		}
+
+		stmt := &gorm.Statement{DB: DB}
+		if err := stmt.Parse(table); err != nil {
+			return err
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || string(field.DataType) != models.BigIntSQLType {
+				continue
+			}
+
+			var column struct {
+				DataType         string
+				NumericPrecision *int
+				NumericScale     *int
+			}
+			err := DB.Raw(`
+				SELECT data_type, numeric_precision, numeric_scale FROM information_schema.columns
+				WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?
+			`, stmt.Schema.Table, field.DBName).Scan(&column).Error
+			if err != nil {
+				return err
+			}
+
+			if column.DataType == "" {
+				continue // колонки еще нет - AutoMigrate создаст ее с нужным типом
+			}
+			if column.DataType == "numeric" && column.NumericPrecision != nil && *column.NumericPrecision == 78 &&
+				column.NumericScale != nil && *column.NumericScale == 0 {
+				continue
+			}
+
+			// Пустые строки становятся NULL, а в NOT NULL колонках - нулем; дробная часть отбрасывается
+			using := fmt.Sprintf(`TRUNC(NULLIF(TRIM(%q::text), '')::numeric)`, field.DBName)
+			if field.NotNull {
+				using = "COALESCE(" + using + ", 0)"
+			}
+
+			// Старый default ('0'::varchar) не приводится к numeric - AutoMigrate вернет его
+			err = DB.Exec(fmt.Sprintf(`ALTER TABLE %q ALTER COLUMN %q DROP DEFAULT, ALTER COLUMN %q TYPE %s USING %s`,
+				stmt.Schema.Table, field.DBName, field.DBName, models.BigIntSQLType, using,
+			)).Error
+			if err != nil {
+				return fmt.Errorf("не удалось изменить тип %s.%s: %v", stmt.Schema.Table, field.DBName, err)
+			}
+
+			logrus.Infof("Колонка %s.%s переведена с %s на %s", stmt.Schema.Table, field.DBName, column.DataType, models.BigIntSQLType)
+		}
+	}
+
+	return nil
+}
+
 func Close() error {
 	if DB == nil {
 		return nil
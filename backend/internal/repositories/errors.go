@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Коды ошибок PostgreSQL (https://www.postgresql.org/docs/current/errcodes-appendix.html)
+const (
+	pgUniqueViolation = "23505"
+	pgCheckViolation  = "23514"
+)
+
+// pgErrorCode возвращает код ошибки PostgreSQL или пустую строку, если ошибка не от Postgres
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// IsUniqueViolation проверяет, что ошибка - нарушение уникального ограничения
+func IsUniqueViolation(err error) bool {
+	return pgErrorCode(err) == pgUniqueViolation
+}
+
+// IsCheckViolation проверяет, что ошибка - нарушение check-ограничения
+func IsCheckViolation(err error) bool {
+	return pgErrorCode(err) == pgCheckViolation
+}
@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Направления обхода ребер относительно адреса
+const (
+	EdgeDirectionOut  = "out"  // исходящие: адрес - отправитель
+	EdgeDirectionIn   = "in"   // входящие: адрес - получатель
+	EdgeDirectionBoth = "both" // в обе стороны
+)
+
+// maxGraphNodes ограничивает число адресов, которые обход графа может посетить за один запрос
+const maxGraphNodes = 10000
+
+type GraphRepository struct {
+	db *gorm.DB
+}
+
+func NewGraphRepository() *GraphRepository {
+	return &GraphRepository{db: DB}
+}
+
+// GraphNode - адрес в окрестности и его расстояние (в ребрах) от исходного адреса
+type GraphNode struct {
+	Address  string
+	Distance int
+}
+
+// Neighborhood - подграф вокруг адреса: посещенные адреса и ребра между ними
+type Neighborhood struct {
+	Nodes     []GraphNode
+	Edges     []models.AddressEdge
+	Truncated bool // обход остановлен по лимиту адресов
+}
+
+// RecordEdge атомарно учитывает перевод актива в ребре from -> to.
+// Переводы с участием нулевого адреса (mint/burn, деплой) в граф не попадают
+func (r *GraphRepository) RecordEdge(from, to, asset string, value *big.Int, timestamp time.Time) error {
+	if from == "" || to == "" || from == models.ZeroAddress || to == models.ZeroAddress {
+		return nil
+	}
+
+	return r.db.Exec(`
+		INSERT INTO address_edges ("from", "to", asset, transaction_count, volume, first_seen, last_seen, created_at, updated_at)
+		VALUES (?, ?, ?, 1, ?, ?, ?, NOW(), NOW())
+		ON CONFLICT ("from", "to", asset) DO UPDATE SET
+			transaction_count = address_edges.transaction_count + 1,
+			volume = address_edges.volume + EXCLUDED.volume,
+			first_seen = LEAST(address_edges.first_seen, EXCLUDED.first_seen),
+			last_seen = GREATEST(address_edges.last_seen, EXCLUDED.last_seen),
+			updated_at = NOW()
+	`, from, to, asset, models.NewBigInt(value), timestamp, timestamp).Error
+}
+
+// IsEmpty проверяет, что граф еще не построен
+func (r *GraphRepository) IsEmpty() (bool, error) {
+	var exists bool
+	err := r.db.Raw(`SELECT EXISTS (SELECT 1 FROM address_edges)`).Row().Scan(&exists)
+	return !exists, err
+}
+
+// Rebuild заново строит граф из сохраненных транзакций и ERC20 трансферов. Повторный вызов дает тот же результат
+func (r *GraphRepository) Rebuild() (int64, error) {
+	var rows int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM address_edges`).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			INSERT INTO address_edges ("from", "to", asset, transaction_count, volume, first_seen, last_seen, created_at, updated_at)
+			SELECT "from", "to", ?, COUNT(*), SUM(value), MIN(timestamp), MAX(timestamp), NOW(), NOW()
+			FROM transactions
+			WHERE "to" != '' AND "to" != ? AND status = 1
+			GROUP BY "from", "to"
+			UNION ALL
+			SELECT "from", "to", contract_address, COUNT(*), SUM(value), MIN(timestamp), MAX(timestamp), NOW(), NOW()
+			FROM erc20_transfers
+			WHERE "from" != ? AND "to" != ?
+			GROUP BY "from", "to", contract_address
+		`, models.EdgeAssetETH, models.ZeroAddress, models.ZeroAddress, models.ZeroAddress)
+		rows = result.RowsAffected
+		return result.Error
+	})
+	return rows, err
+}
+
+// GetEdge возвращает ребро from -> to по активу
+func (r *GraphRepository) GetEdge(from, to, asset string) (*models.AddressEdge, error) {
+	var edge models.AddressEdge
+	err := r.db.Where("\"from\" = ? AND \"to\" = ? AND asset = ?", from, to, asset).First(&edge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &edge, nil
+}
+
+// GetNeighbors возвращает ребра адреса в заданном направлении, от самых частых к редким.
+// Пустой asset - все активы, limit <= 0 - без ограничения
+func (r *GraphRepository) GetNeighbors(address, direction, asset string, limit int) ([]models.AddressEdge, error) {
+	var edges []models.AddressEdge
+	query := r.edgesOf(r.db, []string{address}, direction, asset).
+		Order("transaction_count DESC, id")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&edges).Error
+	return edges, err
+}
+
+// edgeScanner читает ребра, инцидентные адресам, пачками в порядке id (см. scanEdges)
+type edgeScanner func(addresses []string, direction, asset string, batchSize func() int, handle func([]models.AddressEdge) bool) error
+
+// ExpandNeighborhood обходит граф в ширину от адреса на hops шагов в заданном направлении.
+// Обход останавливается, когда посещено maxNodes адресов (не больше maxGraphNodes); ребра шага читаются
+// пачками размером с оставшийся лимит адресов, чтобы у популярных адресов не загружать все ребра разом
+func (r *GraphRepository) ExpandNeighborhood(address string, hops int, direction, asset string, maxNodes int) (*Neighborhood, error) {
+	return expandNeighborhood(r.scanEdges, address, hops, direction, asset, maxNodes)
+}
+
+func expandNeighborhood(scan edgeScanner, address string, hops int, direction, asset string, maxNodes int) (*Neighborhood, error) {
+	if maxNodes <= 0 || maxNodes > maxGraphNodes {
+		maxNodes = maxGraphNodes
+	}
+
+	distances := map[string]int{address: 0}
+	neighborhood := &Neighborhood{Nodes: []GraphNode{{Address: address, Distance: 0}}}
+	seenEdges := make(map[uint]bool)
+	frontier := []string{address}
+
+	for hop := 1; hop <= hops && len(frontier) > 0; hop++ {
+		var next []string
+		batchSize := func() int { return maxNodes - len(distances) + 1 }
+		err := scan(frontier, direction, asset, batchSize, func(edges []models.AddressEdge) bool {
+			for _, edge := range edges {
+				if !seenEdges[edge.ID] {
+					seenEdges[edge.ID] = true
+					neighborhood.Edges = append(neighborhood.Edges, edge)
+				}
+
+				for _, neighbor := range []string{edge.From, edge.To} {
+					if _, visited := distances[neighbor]; visited {
+						continue
+					}
+					if len(distances) >= maxNodes {
+						neighborhood.Truncated = true
+						continue
+					}
+					distances[neighbor] = hop
+					neighborhood.Nodes = append(neighborhood.Nodes, GraphNode{Address: neighbor, Distance: hop})
+					next = append(next, neighbor)
+				}
+			}
+			return !neighborhood.Truncated
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if neighborhood.Truncated {
+			break
+		}
+		frontier = next
+	}
+
+	// Оставляем только ребра между посещенными адресами
+	edges := neighborhood.Edges[:0]
+	for _, edge := range neighborhood.Edges {
+		_, fromVisited := distances[edge.From]
+		_, toVisited := distances[edge.To]
+		if fromVisited && toVisited {
+			edges = append(edges, edge)
+		}
+	}
+	neighborhood.Edges = edges
+
+	return neighborhood, nil
+}
+
+// ShortestPath ищет кратчайший по числу ребер путь переводов от from к to (по направлению ребер), не длиннее maxHops.
+// Возвращает ребра пути по порядку и признак того, что путь найден
+func (r *GraphRepository) ShortestPath(from, to, asset string, maxHops int) ([]models.AddressEdge, bool, error) {
+	return shortestPath(r.scanEdges, from, to, asset, maxHops)
+}
+
+func shortestPath(scan edgeScanner, from, to, asset string, maxHops int) ([]models.AddressEdge, bool, error) {
+	if from == to {
+		return nil, true, nil
+	}
+
+	// Ребро, по которому адрес был достигнут впервые
+	parents := map[string]*models.AddressEdge{from: nil}
+	frontier := []string{from}
+
+	for hop := 1; hop <= maxHops && len(frontier) > 0; hop++ {
+		var next []string
+		found := false
+		batchSize := func() int { return maxGraphNodes - len(parents) + 1 }
+		err := scan(frontier, EdgeDirectionOut, asset, batchSize, func(edges []models.AddressEdge) bool {
+			for i := range edges {
+				edge := &edges[i]
+				if _, visited := parents[edge.To]; visited {
+					continue
+				}
+				parents[edge.To] = edge
+
+				if edge.To == to {
+					found = true
+					return false
+				}
+				next = append(next, edge.To)
+			}
+			return len(parents) < maxGraphNodes
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		if found {
+			return buildPath(parents, to), true, nil
+		}
+
+		if len(parents) >= maxGraphNodes {
+			break
+		}
+		frontier = next
+	}
+
+	return nil, false, nil
+}
+
+// scanEdges читает ребра, инцидентные адресам, пачками в порядке id и передает их в handle, пока тот не вернет false.
+// Размер каждой пачки (LIMIT) берется из batchSize перед запросом
+func (r *GraphRepository) scanEdges(addresses []string, direction, asset string, batchSize func() int, handle func([]models.AddressEdge) bool) error {
+	var lastID uint
+	for {
+		limit := batchSize()
+		if limit <= 0 {
+			return nil
+		}
+
+		var edges []models.AddressEdge
+		err := r.edgesOf(r.db, addresses, direction, asset).
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(limit).
+			Find(&edges).Error
+		if err != nil {
+			return err
+		}
+
+		if len(edges) == 0 || !handle(edges) || len(edges) < limit {
+			return nil
+		}
+		lastID = edges[len(edges)-1].ID
+	}
+}
+
+// edgesOf строит запрос ребер, инцидентных адресам, в заданном направлении
+func (r *GraphRepository) edgesOf(db *gorm.DB, addresses []string, direction, asset string) *gorm.DB {
+	query := db.Model(&models.AddressEdge{})
+	switch direction {
+	case EdgeDirectionOut:
+		query = query.Where("\"from\" IN ?", addresses)
+	case EdgeDirectionIn:
+		query = query.Where("\"to\" IN ?", addresses)
+	default:
+		query = query.Where("\"from\" IN ? OR \"to\" IN ?", addresses, addresses)
+	}
+	if asset != "" {
+		query = query.Where("asset = ?", asset)
+	}
+	return query
+}
+
+// buildPath восстанавливает путь до адреса по ребрам, которыми были достигнуты адреса
+func buildPath(parents map[string]*models.AddressEdge, to string) []models.AddressEdge {
+	var path []models.AddressEdge
+	for edge := parents[to]; edge != nil; edge = parents[edge.From] {
+		path = append([]models.AddressEdge{*edge}, path...)
+	}
+	return path
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"sort"
+	"testing"
+)
+
+// memoryEdges повторяет постраничное чтение scanEdges (id > lastID ORDER BY id LIMIT) по ребрам в памяти
+type memoryEdges []models.AddressEdge
+
+func (m memoryEdges) scan(addresses []string, direction, asset string, batchSize func() int, handle func([]models.AddressEdge) bool) error {
+	inSet := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		inSet[address] = true
+	}
+
+	var matched []models.AddressEdge
+	for _, edge := range m {
+		if asset != "" && edge.Asset != asset {
+			continue
+		}
+		switch direction {
+		case EdgeDirectionOut:
+			if !inSet[edge.From] {
+				continue
+			}
+		case EdgeDirectionIn:
+			if !inSet[edge.To] {
+				continue
+			}
+		default:
+			if !inSet[edge.From] && !inSet[edge.To] {
+				continue
+			}
+		}
+		matched = append(matched, edge)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+
+	for len(matched) > 0 {
+		limit := batchSize()
+		if limit <= 0 {
+			return nil
+		}
+		page := matched
+		if len(page) > limit {
+			page = page[:limit]
+		}
+		if !handle(append([]models.AddressEdge(nil), page...)) || len(page) < limit {
+			return nil
+		}
+		matched = matched[len(page):]
+	}
+	return nil
+}
+
+func edge(id uint, from, to string) models.AddressEdge {
+	return models.AddressEdge{ID: id, From: from, To: to, Asset: models.EdgeAssetETH}
+}
+
+func TestExpandNeighborhoodTruncates(t *testing.T) {
+	graph := memoryEdges{
+		edge(1, "hub", "a"),
+		edge(2, "hub", "b"),
+		edge(3, "hub", "c"),
+		edge(4, "hub", "d"),
+		edge(5, "a", "b"),
+	}
+
+	got, err := expandNeighborhood(graph.scan, "hub", 2, EdgeDirectionBoth, "", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Truncated {
+		t.Error("обход должен быть остановлен по лимиту адресов")
+	}
+	want := []GraphNode{{"hub", 0}, {"a", 1}, {"b", 1}}
+	if len(got.Nodes) != len(want) {
+		t.Fatalf("адреса: %+v, ожидалось %+v", got.Nodes, want)
+	}
+	for i := range want {
+		if got.Nodes[i] != want[i] {
+			t.Errorf("адрес #%d: %+v, ожидалось %+v", i, got.Nodes[i], want[i])
+		}
+	}
+
+	// Ребро hub -> c было прочитано, но c не посещен: в подграф оно не попадает
+	for _, e := range got.Edges {
+		if e.To == "c" || e.To == "d" {
+			t.Errorf("ребро к непосещенному адресу %s в подграфе", e.To)
+		}
+	}
+	if len(got.Edges) != 2 {
+		t.Errorf("ожидалось 2 ребра, получено %d", len(got.Edges))
+	}
+}
+
+func TestExpandNeighborhoodDirection(t *testing.T) {
+	graph := memoryEdges{
+		edge(1, "a", "b"),
+		edge(2, "b", "c"),
+		edge(3, "c", "d"),
+		edge(4, "x", "b"),
+	}
+
+	got, err := expandNeighborhood(graph.scan, "c", 2, EdgeDirectionIn, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	distances := make(map[string]int)
+	for _, node := range got.Nodes {
+		distances[node.Address] = node.Distance
+	}
+	if len(distances) != 4 || distances["b"] != 1 || distances["a"] != 2 || distances["x"] != 2 {
+		t.Errorf("неверные расстояния по входящим ребрам: %v", distances)
+	}
+	if _, ok := distances["d"]; ok {
+		t.Error("исходящее ребро c -> d не должно обходиться")
+	}
+	if got.Truncated {
+		t.Error("лимит адресов не достигнут")
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	graph := memoryEdges{
+		edge(1, "a", "b"),
+		edge(2, "a", "c"),
+		edge(3, "c", "d"),
+		edge(4, "b", "d"),
+		edge(5, "d", "e"),
+	}
+
+	path, found, err := shortestPath(graph.scan, "a", "e", "", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("путь a -> e не найден")
+	}
+	// d впервые достигнут по ребру c -> d (id 3 читается раньше b -> d)
+	wantIDs := []uint{2, 3, 5}
+	if len(path) != len(wantIDs) {
+		t.Fatalf("путь из %d ребер, ожидалось %d: %+v", len(path), len(wantIDs), path)
+	}
+	for i, id := range wantIDs {
+		if path[i].ID != id {
+			t.Errorf("ребро #%d пути: id %d, ожидалось %d", i, path[i].ID, id)
+		}
+	}
+
+	if _, found, _ := shortestPath(graph.scan, "a", "e", "", 2); found {
+		t.Error("путь длиннее maxHops не должен находиться")
+	}
+	if _, found, _ := shortestPath(graph.scan, "e", "a", "", 5); found {
+		t.Error("путь против направления ребер не должен находиться")
+	}
+	if path, found, _ := shortestPath(graph.scan, "a", "a", "", 0); !found || path != nil {
+		t.Errorf("путь до самого себя: %v %+v", found, path)
+	}
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HolderRepository struct {
+	db *gorm.DB
+}
+
+func NewHolderRepository() *HolderRepository {
+	return &HolderRepository{db: DB}
+}
+
+// HolderBalance - баланс держателя токена
+type HolderBalance struct {
+	Address string
+	Balance models.BigInt
+}
+
+// HasLedgerHistory проверяет, ведется ли по токену леджер балансов
+func (r *HolderRepository) HasLedgerHistory(tokenAddress string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.BalanceChange{}).
+		Where("token_address = ?", tokenAddress).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetLedgerHolderBalances возвращает ненулевые балансы держателей на конец блока по леджеру, от крупных к мелким
+func (r *HolderRepository) GetLedgerHolderBalances(tokenAddress string, block uint64) ([]HolderBalance, error) {
+	var balances []HolderBalance
+	err := r.db.Raw(`
+		SELECT address, balance FROM (
+			SELECT DISTINCT ON (address) address, balance
+			FROM balance_changes
+			WHERE token_address = ? AND block_number <= ? AND address != ?
+			ORDER BY address, block_number DESC, tx_index DESC, log_index DESC, id DESC
+		) AS latest
+		WHERE balance > 0
+		ORDER BY balance DESC, address
+	`, tokenAddress, block, models.ZeroAddress).Scan(&balances).Error
+	return balances, err
+}
+
+// GetStoredHolderBalances возвращает ненулевые текущие балансы держателей из token_balances, от крупных к мелким
+func (r *HolderRepository) GetStoredHolderBalances(tokenAddress string) ([]HolderBalance, error) {
+	var balances []HolderBalance
+	err := r.db.Model(&models.TokenBalance{}).
+		Select("address, balance").
+		Where("token_address = ? AND balance > 0 AND address != ?", tokenAddress, models.ZeroAddress).
+		Order("balance DESC, address").
+		Scan(&balances).Error
+	return balances, err
+}
+
+// SaveSnapshot сохраняет снимок распределения вместе с крупнейшими держателями.
+// Повторный снимок того же блока игнорируется; возвращает false, если снимок уже был
+func (r *HolderRepository) SaveSnapshot(snapshot *models.HolderDistributionSnapshot) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("TopHolders").Create(snapshot)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+
+		if len(snapshot.TopHolders) == 0 {
+			return nil
+		}
+		for i := range snapshot.TopHolders {
+			snapshot.TopHolders[i].SnapshotID = snapshot.ID
+		}
+		return tx.Create(&snapshot.TopHolders).Error
+	})
+	return created, err
+}
+
+// GetLatestSnapshot возвращает последний снимок распределения токена с крупнейшими держателями
+func (r *HolderRepository) GetLatestSnapshot(contractAddress string) (*models.HolderDistributionSnapshot, error) {
+	var snapshot models.HolderDistributionSnapshot
+	err := r.db.Preload("TopHolders", func(db *gorm.DB) *gorm.DB { return db.Order("rank") }).
+		Where("contract_address = ?", contractAddress).
+		Order("block_number DESC").
+		First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetSnapshotAtBlock возвращает последний снимок не позже блока
+func (r *HolderRepository) GetSnapshotAtBlock(contractAddress string, block uint64) (*models.HolderDistributionSnapshot, error) {
+	var snapshot models.HolderDistributionSnapshot
+	err := r.db.Preload("TopHolders", func(db *gorm.DB) *gorm.DB { return db.Order("rank") }).
+		Where("contract_address = ? AND block_number <= ?", contractAddress, block).
+		Order("block_number DESC").
+		First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetSnapshotHistory возвращает метрики распределения за период (без списка держателей), в хронологическом порядке
+func (r *HolderRepository) GetSnapshotHistory(contractAddress string, from, to time.Time) ([]models.HolderDistributionSnapshot, error) {
+	var snapshots []models.HolderDistributionSnapshot
+	err := r.db.Where("contract_address = ? AND snapshot_at >= ? AND snapshot_at <= ?", contractAddress, from, to).
+		Order("block_number").
+		Find(&snapshots).Error
+	return snapshots, err
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ListenerCheckpointRepository struct {
+	db *gorm.DB
+}
+
+func NewListenerCheckpointRepository() *ListenerCheckpointRepository {
+	return &ListenerCheckpointRepository{db: DB}
+}
+
+// LoadCheckpoint возвращает последний блок, события которого слушатель контракта передал в приемник; 0 - чекпоинта нет
+func (r *ListenerCheckpointRepository) LoadCheckpoint(contractAddress string) (uint64, error) {
+	var checkpoint models.ListenerCheckpoint
+	err := r.db.Where("contract_address = ?", contractAddress).First(&checkpoint).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return checkpoint.BlockNumber, nil
+}
+
+// SaveCheckpoint сохраняет чекпоинт слушателя контракта
+func (r *ListenerCheckpointRepository) SaveCheckpoint(contractAddress string, block uint64) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "contract_address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block_number", "updated_at"}),
+	}).Create(&models.ListenerCheckpoint{
+		ContractAddress: contractAddress,
+		BlockNumber:     block,
+		UpdatedAt:       time.Now(),
+	}).Error
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const accountStatsBatchSize = 1000 // адресов в одном запросе IN
+
+type ReconciliationRepository struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepository() *ReconciliationRepository {
+	return &ReconciliationRepository{db: DB}
+}
+
+// TokenTransferTotals - агрегаты по ERC20 трансферам аккаунта
+type TokenTransferTotals struct {
+	TransferCount uint64
+	Sent          *big.Int
+	Received      *big.Int
+}
+
+// GetLastProcessedBlock возвращает последний полностью проиндексированный блок
+func (r *ReconciliationRepository) GetLastProcessedBlock() (uint64, error) {
+	var state models.AnalyzerState
+	err := r.db.First(&state).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	return state.LastProcessedBlock, err
+}
+
+// GetTokenParticipants возвращает все адреса, участвовавшие в трансферах токена (без нулевого адреса mint/burn)
+func (r *ReconciliationRepository) GetTokenParticipants(contractAddress string) ([]string, error) {
+	var addresses []string
+	err := r.db.Raw(`
+		SELECT "from" AS address FROM erc20_transfers WHERE contract_address = ? AND "from" != ?
+		UNION
+		SELECT "to" AS address FROM erc20_transfers WHERE contract_address = ? AND "to" != ?
+	`, contractAddress, models.ZeroAddress, contractAddress, models.ZeroAddress).Scan(&addresses).Error
+	return addresses, err
+}
+
+// GetAccountStats возвращает сохраненную статистику аккаунтов по адресу (аккаунты без статистики отсутствуют)
+func (r *ReconciliationRepository) GetAccountStats(addresses []string) (map[string]*models.AccountStats, error) {
+	stats := make(map[string]*models.AccountStats, len(addresses))
+
+	for start := 0; start < len(addresses); start += accountStatsBatchSize {
+		end := start + accountStatsBatchSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		var batch []models.AccountStats
+		if err := r.db.Where("address IN ?", addresses[start:end]).Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			stats[batch[i].Address] = &batch[i]
+		}
+	}
+
+	return stats, nil
+}
+
+// GetTokenTransferTotals считает количество трансферов и объемы аккаунта до блока включительно.
+// Учитываются только трансферы, которые считает сам контракт (recorded): без mint, burn и transferFrom
+func (r *ReconciliationRepository) GetTokenTransferTotals(address, contractAddress string, toBlock uint64) (*TokenTransferTotals, error) {
+	var row struct {
+		TransferCount uint64
+		Sent          models.BigInt
+		Received      models.BigInt
+	}
+
+	err := r.db.Model(&models.ERC20Transfer{}).
+		Select(`
+			COUNT(*) as transfer_count,
+			COALESCE(SUM(CASE WHEN "from" = ? THEN value ELSE 0 END), 0) as sent,
+			COALESCE(SUM(CASE WHEN "to" = ? THEN value ELSE 0 END), 0) as received
+		`, address, address).
+		Where("contract_address = ? AND (\"from\" = ? OR \"to\" = ?) AND block_number <= ? AND recorded",
+			contractAddress, address, address, toBlock).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenTransferTotals{
+		TransferCount: row.TransferCount,
+		Sent:          row.Sent.Int(),
+		Received:      row.Received.Int(),
+	}, nil
+}
+
+// GetTokenVolumeForPeriod считает отправленный и полученный объем токена за период (границы включительно)
+// по трансферам, которые считает сам контракт (recorded)
+func (r *ReconciliationRepository) GetTokenVolumeForPeriod(address, contractAddress string, from, to time.Time) (*big.Int, *big.Int, error) {
+	var row struct {
+		Sent     models.BigInt
+		Received models.BigInt
+	}
+
+	err := r.db.Model(&models.ERC20Transfer{}).
+		Select(`
+			COALESCE(SUM(CASE WHEN "from" = ? THEN value ELSE 0 END), 0) as sent,
+			COALESCE(SUM(CASE WHEN "to" = ? THEN value ELSE 0 END), 0) as received
+		`, address, address).
+		Where("contract_address = ? AND (\"from\" = ? OR \"to\" = ?) AND timestamp >= ? AND timestamp <= ? AND recorded",
+			contractAddress, address, address, from, to).
+		Scan(&row).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return row.Sent.Int(), row.Received.Int(), nil
+}
+
+// CountConfirmedRelays возвращает количество внешних транзакций аккаунта, записанных в контракт
+func (r *ReconciliationRepository) CountConfirmedRelays(address string) (uint64, error) {
+	var count int64
+	err := r.db.Model(&models.ExternalTransactionRelay{}).
+		Where("status = ? AND (\"from\" = ? OR \"to\" = ?)", models.RelayStatusConfirmed, address, address).
+		Count(&count).Error
+	return uint64(count), err
+}
+
+// SaveDiscrepancies сохраняет отчет о расхождениях
+func (r *ReconciliationRepository) SaveDiscrepancies(discrepancies []models.StatsDiscrepancy) error {
+	if len(discrepancies) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(discrepancies, 100).Error
+}
+
+// GetDiscrepanciesByRun возвращает расхождения, найденные в конкретном запуске
+func (r *ReconciliationRepository) GetDiscrepanciesByRun(runID string) ([]models.StatsDiscrepancy, error) {
+	var discrepancies []models.StatsDiscrepancy
+	err := r.db.Where("run_id = ?", runID).Order("drift_percent DESC").Find(&discrepancies).Error
+	return discrepancies, err
+}
+
+// GetLatestDiscrepancies возвращает расхождения аккаунта, начиная с самых свежих
+func (r *ReconciliationRepository) GetLatestDiscrepancies(address string, limit int) ([]models.StatsDiscrepancy, error) {
+	var discrepancies []models.StatsDiscrepancy
+	err := r.db.Where("address = ?", address).Order("checked_at DESC").Limit(limit).Find(&discrepancies).Error
+	return discrepancies, err
+}
+
+// GetStoredTokenBalances возвращает все сохраненные балансы токена
+func (r *ReconciliationRepository) GetStoredTokenBalances(tokenAddress string) ([]models.TokenBalance, error) {
+	var balances []models.TokenBalance
+	err := r.db.Where("token_address = ?", tokenAddress).Find(&balances).Error
+	return balances, err
+}
+
+// ApplyBalanceCorrections исправляет балансы и записывает исправления в одной транзакции.
+// Баланс сдвигается на разницу (on-chain минус сохраненный), а не перезаписывается: трансферы новых блоков,
+// учтенные анализатором между чтением и исправлением, при этом сохраняются
+func (r *ReconciliationRepository) ApplyBalanceCorrections(corrections []models.BalanceCorrection) error {
+	if len(corrections) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, correction := range corrections {
+			if _, err := adjustTokenBalance(tx, correction.Address, correction.TokenAddress, correction.Difference.Int()); err != nil {
+				return err
+			}
+		}
+
+		return tx.CreateInBatches(corrections, 100).Error
+	})
+}
+
+// GetBalanceCorrectionsByRun возвращает исправления балансов, сделанные в конкретном запуске
+func (r *ReconciliationRepository) GetBalanceCorrectionsByRun(runID string) ([]models.BalanceCorrection, error) {
+	var corrections []models.BalanceCorrection
+	err := r.db.Where("run_id = ?", runID).Order("address").Find(&corrections).Error
+	return corrections, err
+}
+
+// GetLatestBalanceCorrections возвращает исправления балансов аккаунта, начиная с самых свежих
+func (r *ReconciliationRepository) GetLatestBalanceCorrections(address string, limit int) ([]models.BalanceCorrection, error) {
+	var corrections []models.BalanceCorrection
+	err := r.db.Where("address = ?", address).Order("corrected_at DESC").Limit(limit).Find(&corrections).Error
+	return corrections, err
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RelayRepository struct {
+	db *gorm.DB
+}
+
+func NewRelayRepository() *RelayRepository {
+	return &RelayRepository{db: DB}
+}
+
+// Enqueue ставит внешнюю транзакцию в очередь ретрансляции (повторная постановка игнорируется)
+func (r *RelayRepository) Enqueue(relay *models.ExternalTransactionRelay) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(relay).Error
+}
+
+// GetReadyToSend возвращает записи, ожидающие отправки, в порядке появления
+func (r *RelayRepository) GetReadyToSend(limit int) ([]models.ExternalTransactionRelay, error) {
+	var relays []models.ExternalTransactionRelay
+	err := r.db.Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", models.RelayStatusPending, time.Now()).
+		Order("block_number ASC, id ASC").
+		Limit(limit).
+		Find(&relays).Error
+	return relays, err
+}
+
+// GetSent возвращает отправленные, но еще не подтвержденные записи
+func (r *RelayRepository) GetSent() ([]models.ExternalTransactionRelay, error) {
+	var relays []models.ExternalTransactionRelay
+	err := r.db.Where("status = ?", models.RelayStatusSent).Order("id ASC").Find(&relays).Error
+	return relays, err
+}
+
+// MarkSent фиксирует отправку транзакции ретрансляции
+func (r *RelayRepository) MarkSent(id uint, relayTxHash string, nonce uint64) error {
+	return r.db.Model(&models.ExternalTransactionRelay{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":        models.RelayStatusSent,
+			"relay_tx_hash": relayTxHash,
+			"relay_nonce":   nonce,
+			"sent_at":       time.Now(),
+			"attempts":      gorm.Expr("attempts + 1"),
+			"last_error":    "",
+		}).Error
+}
+
+// UpdateRelayTxHash обновляет хеш после замены транзакции с повышенной комиссией
+func (r *RelayRepository) UpdateRelayTxHash(id uint, relayTxHash string) error {
+	return r.db.Model(&models.ExternalTransactionRelay{}).Where("id = ?", id).
+		Update("relay_tx_hash", relayTxHash).Error
+}
+
+// MarkConfirmed отмечает успешную запись в контракт
+func (r *RelayRepository) MarkConfirmed(id uint) error {
+	return r.db.Model(&models.ExternalTransactionRelay{}).Where("id = ?", id).
+		Update("status", models.RelayStatusConfirmed).Error
+}
+
+// MarkRetry возвращает запись в очередь с отложенной повторной попыткой
+func (r *RelayRepository) MarkRetry(id uint, lastError string, nextAttemptAt time.Time) error {
+	return r.db.Model(&models.ExternalTransactionRelay{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          models.RelayStatusPending,
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+// MarkFailed окончательно отмечает запись неуспешной
+func (r *RelayRepository) MarkFailed(id uint, lastError string) error {
+	return r.db.Model(&models.ExternalTransactionRelay{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.RelayStatusFailed,
+			"last_error": lastError,
+		}).Error
+}
+
+// CountByStatus возвращает количество записей в каждом статусе
+func (r *RelayRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.Model(&models.ExternalTransactionRelay{}).
+		Select("status, COUNT(*) as count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SignatureRepository struct {
+	db *gorm.DB
+}
+
+func NewSignatureRepository() *SignatureRepository {
+	return &SignatureRepository{db: DB}
+}
+
+// ImportSignatures добавляет сигнатуры в локальную базу, уже известные пропускаются
+func (r *SignatureRepository) ImportSignatures(signatures []models.MethodSignature) (int64, error) {
+	if len(signatures) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(signatures, 500)
+	return result.RowsAffected, result.Error
+}
+
+// GetAllSignatures возвращает всю локальную базу сигнатур
+func (r *SignatureRepository) GetAllSignatures() ([]models.MethodSignature, error) {
+	var signatures []models.MethodSignature
+	err := r.db.Order("id ASC").Find(&signatures).Error
+	return signatures, err
+}
+
+// GetSignaturesBySelector возвращает все сигнатуры с указанным селектором
+func (r *SignatureRepository) GetSignaturesBySelector(selector string) ([]models.MethodSignature, error) {
+	var signatures []models.MethodSignature
+	err := r.db.Where("selector = ?", selector).Order("id ASC").Find(&signatures).Error
+	return signatures, err
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SupplyRepository struct {
+	db *gorm.DB
+}
+
+func NewSupplyRepository() *SupplyRepository {
+	return &SupplyRepository{db: DB}
+}
+
+// GetOpeningSupply возвращает начальную запись истории эмиссии токена или nil, если ее еще нет
+func (r *SupplyRepository) GetOpeningSupply(contractAddress string) (*models.TotalSupplySnapshot, error) {
+	var snapshot models.TotalSupplySnapshot
+	err := r.db.Where("contract_address = ? AND opening", contractAddress).First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// SaveOpeningSupply сохраняет начальную эмиссию токена на блоке, прочитанную из контракта
+func (r *SupplyRepository) SaveOpeningSupply(contractAddress string, block uint64, supply *big.Int) error {
+	now := time.Now()
+	onChain := models.NewBigInt(supply)
+	return r.db.Create(&models.TotalSupplySnapshot{
+		ContractAddress: contractAddress,
+		BlockNumber:     block,
+		TotalSupply:     onChain,
+		OnChainSupply:   &onChain,
+		Verified:        true,
+		Matches:         true,
+		Opening:         true,
+		CheckedAt:       &now,
+	}).Error
+}
+
+// GetFirstTransferBlock возвращает первый блок с проиндексированным трансфером токена
+func (r *SupplyRepository) GetFirstTransferBlock(contractAddress string) (uint64, error) {
+	var block uint64
+	err := r.db.Model(&models.ERC20Transfer{}).
+		Where("contract_address = ?", contractAddress).
+		Select("COALESCE(MIN(block_number), 0)").
+		Row().Scan(&block)
+	return block, err
+}
+
+// RebuildSupplyHistory пересчитывает историю эмиссии токена по событиям mint и burn после начальной записи:
+// накопленная эмиссия - начальная плюс сумма mint минус burn. Без начальной записи отсчет идет от нуля.
+// Пересчет идемпотентен: запись остается проверенной, только если накопленная эмиссия не изменилась
+func (r *SupplyRepository) RebuildSupplyHistory(contractAddress string) (int64, error) {
+	result := r.db.Exec(`
+		WITH opening AS (
+			SELECT MAX(block_number) AS opening_block, COALESCE(MAX(total_supply), 0) AS opening_supply
+			FROM total_supply_history WHERE contract_address = @contract AND opening
+		)
+		INSERT INTO total_supply_history
+			(contract_address, block_number, minted, burned, total_supply, verified, matches, created_at, updated_at)
+		SELECT
+			contract_address,
+			block_number,
+			SUM(CASE WHEN kind = @mint THEN value ELSE 0 END),
+			SUM(CASE WHEN kind = @burn THEN value ELSE 0 END),
+			MAX(opening_supply) + SUM(SUM(CASE WHEN kind = @mint THEN value ELSE -value END))
+				OVER (PARTITION BY contract_address ORDER BY block_number),
+			false, false, NOW(), NOW()
+		FROM erc20_transfers, opening
+		WHERE contract_address = @contract AND kind IN (@mint, @burn)
+			AND (opening_block IS NULL OR block_number > opening_block)
+		GROUP BY contract_address, block_number
+		ON CONFLICT (contract_address, block_number) DO UPDATE SET
+			minted = EXCLUDED.minted,
+			burned = EXCLUDED.burned,
+			total_supply = EXCLUDED.total_supply,
+			verified = total_supply_history.verified AND total_supply_history.total_supply = EXCLUDED.total_supply,
+			updated_at = NOW()
+		WHERE total_supply_history.total_supply IS DISTINCT FROM EXCLUDED.total_supply
+			OR total_supply_history.minted IS DISTINCT FROM EXCLUDED.minted
+			OR total_supply_history.burned IS DISTINCT FROM EXCLUDED.burned
+	`, map[string]interface{}{
+		"contract": contractAddress,
+		"mint":     models.TransferKindMint,
+		"burn":     models.TransferKindBurn,
+	})
+
+	return result.RowsAffected, result.Error
+}
+
+// GetUnverifiedSnapshots возвращает записи истории, еще не сверенные с контрактом
+func (r *SupplyRepository) GetUnverifiedSnapshots(contractAddress string, limit int) ([]models.TotalSupplySnapshot, error) {
+	var snapshots []models.TotalSupplySnapshot
+	err := r.db.Where("contract_address = ? AND verified = ?", contractAddress, false).
+		Order("block_number").
+		Limit(limit).
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// MarkVerified сохраняет результат сверки записи с totalSupply контракта
+func (r *SupplyRepository) MarkVerified(id uint, onChainSupply *big.Int, matches bool) error {
+	return r.db.Model(&models.TotalSupplySnapshot{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"on_chain_supply": models.NewBigInt(onChainSupply),
+			"verified":        true,
+			"matches":         matches,
+			"checked_at":      time.Now(),
+		}).Error
+}
+
+// GetSupplyHistory возвращает историю эмиссии токена в диапазоне блоков (границы включительно)
+func (r *SupplyRepository) GetSupplyHistory(contractAddress string, fromBlock, toBlock uint64) ([]models.TotalSupplySnapshot, error) {
+	var snapshots []models.TotalSupplySnapshot
+	err := r.db.Where("contract_address = ? AND block_number >= ? AND block_number <= ?", contractAddress, fromBlock, toBlock).
+		Order("block_number").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// GetSupplyAtBlock возвращает эмиссию токена на блоке - последнюю запись истории не позже блока
+func (r *SupplyRepository) GetSupplyAtBlock(contractAddress string, block uint64) (*models.TotalSupplySnapshot, error) {
+	var snapshot models.TotalSupplySnapshot
+	err := r.db.Where("contract_address = ? AND block_number <= ?", contractAddress, block).
+		Order("block_number DESC").
+		First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetSupplyContracts возвращает контракты, по которым проиндексированы mint или burn
+func (r *SupplyRepository) GetSupplyContracts() ([]string, error) {
+	var contracts []string
+	err := r.db.Model(&models.ERC20Transfer{}).
+		Where("kind IN ?", []string{models.TransferKindMint, models.TransferKindBurn}).
+		Distinct("contract_address").
+		Pluck("contract_address", &contracts).Error
+	return contracts, err
+}
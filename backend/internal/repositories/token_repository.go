@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{db: DB}
+}
+
+// GetToken возвращает метаданные токена или gorm.ErrRecordNotFound
+func (r *TokenRepository) GetToken(contractAddress string) (*models.Token, error) {
+	var token models.Token
+	if err := r.db.Where("contract_address = ?", contractAddress).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// SaveToken создает или обновляет метаданные токена
+func (r *TokenRepository) SaveToken(token *models.Token) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "contract_address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "symbol", "decimals", "total_supply", "supply_updated_at", "updated_at"}),
+	}).Create(token).Error
+}
+
+// UpdateTotalSupply обновляет только общее предложение токена
+func (r *TokenRepository) UpdateTotalSupply(contractAddress string, totalSupply models.BigInt, updatedAt time.Time) error {
+	return r.db.Model(&models.Token{}).
+		Where("contract_address = ?", contractAddress).
+		Updates(map[string]interface{}{
+			"total_supply":      totalSupply,
+			"supply_updated_at": updatedAt,
+		}).Error
+}
+
+// GetAllTokens возвращает все известные токены
+func (r *TokenRepository) GetAllTokens() ([]models.Token, error) {
+	var tokens []models.Token
+	err := r.db.Order("id ASC").Find(&tokens).Error
+	return tokens, err
+}
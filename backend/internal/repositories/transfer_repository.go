@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveTransferForAccounting сохраняет трансфер, если его еще нет, и помечает учтенным.
+// Возвращает true, только если пометку выставил этот вызов: тогда вызывающий должен обновить
+// статистику, балансы и граф. Трансфер, сохраненный слушателем событий, остается неучтенным,
+// пока его не обработает анализатор, поэтому решение принимается не по тому, чья вставка прошла.
+// Признак Recorded слушатель определить не может, поэтому его выставляет тот же учитывающий вызов
+func SaveTransferForAccounting(db *gorm.DB, transfer *models.ERC20Transfer) (bool, error) {
+	transfer.Accounted = false
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(transfer).Error; err != nil {
+		return false, err
+	}
+
+	result := db.Model(&models.ERC20Transfer{}).
+		Where("transaction_hash = ? AND log_index = ? AND NOT accounted", transfer.TransactionHash, transfer.LogIndex).
+		Updates(map[string]interface{}{"accounted": true, "recorded": transfer.Recorded})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	transfer.Accounted = result.RowsAffected > 0
+	return transfer.Accounted, nil
+}
@@ -108,41 +108,21 @@ func (a *AccountAnalyzer) updateSingleAccountStats(address string, tx *models.Tr
 		}
 	}
 
-	stats, err := a.accountRepo.GetOrCreateAccountStats(address)
-	if err != nil {
+	// Счетчики, объемы, комиссии (для отправителя) и время активности обновляются атомарно в SQL
+	if err := a.accountRepo.RecordTransactionStats(address, isSender, tx); err != nil {
 		return err
 	}
 
-	// Обновляем общие метрики только для отправителя
-	if isSender {
-		stats.TotalTransactions++
-	}
-
-	// Обновляем время активности
-	if stats.LastActivityTime == nil || tx.Timestamp.After(*stats.LastActivityTime) {
-		stats.LastActivityTime = &tx.Timestamp
-	}
-
-	if stats.FirstActivityTime == nil || tx.Timestamp.Before(*stats.FirstActivityTime) {
-		stats.FirstActivityTime = &tx.Timestamp
-	}
-
-	// Обновляем объем (только для отправителя)
-	if isSender {
-		currentVolume := stats.GetTotalVolumeETH()
-		txValue := tx.GetValue()
-		newVolume := currentVolume.Add(currentVolume, txValue)
-		stats.SetTotalVolumeETH(newVolume)
+	// Обновляем баланс ETH
+	stats, err := a.accountRepo.GetOrCreateAccountStats(address)
+	if err != nil {
+		return err
 	}
 
-	// Обновляем баланс ETH
 	if err := a.calculator.CalculateETHBalance(address, stats); err != nil {
 		logrus.Errorf("Ошибка обновления баланса ETH для %s: %v", address, err)
 		// Не возвращаем ошибку, чтобы не прерывать обновление других метрик
-	}
-
-	// Сохраняем обновленную статистику
-	if err := a.accountRepo.UpdateAccountStats(stats); err != nil {
+	} else if err := a.accountRepo.UpdateAccountStats(stats); err != nil {
 		return err
 	}
 
@@ -171,6 +151,31 @@ func (a *AccountAnalyzer) GetTokenTracker() *TokenTracker {
 	return a.tokenTracker
 }
 
+// GetTokenVolume возвращает отправленный, полученный и чистый объем токена аккаунта за период [from, to)
+func (a *AccountAnalyzer) GetTokenVolume(address, tokenAddress string, from, to time.Time) (*repositories.TokenVolume, error) {
+	volume, err := a.tokenTracker.CalculateTokenVolume(address, tokenAddress, from, to)
+	if err != nil {
+		logrus.Errorf("Ошибка расчета объема токена %s для %s: %v", tokenAddress, address, err)
+		return nil, err
+	}
+	return volume, nil
+}
+
+// GetTrackedTokenVolume возвращает объем отслеживаемого токена аккаунта за период [from, to)
+func (a *AccountAnalyzer) GetTrackedTokenVolume(address string, from, to time.Time) (*repositories.TokenVolume, error) {
+	return a.GetTokenVolume(address, a.tokenAddress.Hex(), from, to)
+}
+
+// GetTokenVolumes возвращает объемы аккаунта за период [from, to) по всем токенам
+func (a *AccountAnalyzer) GetTokenVolumes(address string, from, to time.Time) ([]repositories.TokenVolume, error) {
+	volumes, err := a.tokenTracker.CalculateTokenVolumes(address, from, to)
+	if err != nil {
+		logrus.Errorf("Ошибка расчета объемов токенов для %s: %v", address, err)
+		return nil, err
+	}
+	return volumes, nil
+}
+
 // GetAccountActivityForPeriod получает активность аккаунта за конкретный период
 func (a *AccountAnalyzer) GetAccountActivityForPeriod(address string, period time.Time) (*models.AccountActivity, error) {
 	activity, err := a.accountRepo.GetAccountActivityForPeriod(address, period)
@@ -185,12 +190,32 @@ func (a *AccountAnalyzer) GetAccountActivityForPeriod(address string, period tim
 	return activity, nil
 }
 
-// GetCurrentPeriodActivity получает активность аккаунта за текущий 15-секундный период
+// GetCurrentPeriodActivity получает активность аккаунта за текущий базовый период
 func (a *AccountAnalyzer) GetCurrentPeriodActivity(address string) (*models.AccountActivity, error) {
 	currentPeriod := utils.GetCurrentPeriodStart()
 	return a.GetAccountActivityForPeriod(address, currentPeriod)
 }
 
+// GetActivitySeries возвращает ряд активности аккаунта за [from, to) и выбранное для него разрешение
+func (a *AccountAnalyzer) GetActivitySeries(address string, from, to time.Time) (string, []models.AccountActivityRollup, error) {
+	resolution, series, err := a.accountRepo.GetActivitySeries(address, from, to)
+	if err != nil {
+		logrus.Errorf("Ошибка получения ряда активности %s за %v - %v: %v", address, from, to, err)
+		return "", nil, err
+	}
+	return resolution, series, nil
+}
+
+// GetGasByContract возвращает расход газа и комиссии аккаунта по вызванным контрактам за [from, to)
+func (a *AccountAnalyzer) GetGasByContract(address string, from, to time.Time) ([]repositories.ContractGasUsage, error) {
+	usage, err := a.accountRepo.GetGasByContract(address, from, to)
+	if err != nil {
+		logrus.Errorf("Ошибка получения расхода газа %s за %v - %v: %v", address, from, to, err)
+		return nil, err
+	}
+	return usage, nil
+}
+
 // GetActiveAccountsForPeriod получает активность всех аккаунтов за конкретный период
 func (a *AccountAnalyzer) GetActiveAccountsForPeriod(period time.Time) ([]models.AccountActivity, error) {
 	activities, err := a.accountRepo.GetAllAccountsActivityForPeriod(period)
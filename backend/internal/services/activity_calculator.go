@@ -7,7 +7,9 @@ import (
 	"backend/pkg/ethereum"
 	"context"
 	"fmt"
+	"math"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,15 +17,19 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// recomputeChunkSize - размер интервала, активность за который пересчитывается за один проход
+const recomputeChunkSize = time.Hour
+
 var (
 	contractCache    = make(map[string]bool)
 	contractCacheMux sync.RWMutex
 )
 
 type ActivityCalculator struct {
-	accountRepo  *repositories.AccountRepository
-	ethClient    *ethereum.Client
-	tokenAddress common.Address // Адрес нашего токена
+	accountRepo   *repositories.AccountRepository
+	ethClient     *ethereum.Client
+	balanceLedger *BalanceLedger
+	tokenAddress  common.Address // Адрес нашего токена
 }
 
 func NewActivityCalculator(accountRepo *repositories.AccountRepository, ethClient *ethereum.Client, tokenAddress common.Address) *ActivityCalculator {
@@ -41,6 +47,11 @@ func NewActivityCalculatorDeprecated() *ActivityCalculator {
 	}
 }
 
+// SetBalanceLedger подключает леджер, из которого берется баланс ETH вместо запроса к ноде
+func (c *ActivityCalculator) SetBalanceLedger(ledger *BalanceLedger) {
+	c.balanceLedger = ledger
+}
+
 // isContract проверяет, является ли адрес контрактом
 func (c *ActivityCalculator) isContract(address string) bool {
 	if c.ethClient == nil {
@@ -89,7 +100,7 @@ func (c *ActivityCalculator) CalculateTransactionFrequency(address string, stats
 		return err
 	}
 
-	logrus.Debugf("Аккаунт %s: транзакций за 24ч = %d, за 15 сек = %d", address, txCount, periodTxCount)
+	logrus.Debugf("Аккаунт %s: транзакций за 24ч = %d, за период = %d", address, txCount, periodTxCount)
 	return nil
 }
 
@@ -102,7 +113,7 @@ func (c *ActivityCalculator) CalculateVolumeMetrics(address string, stats *model
 		return err
 	}
 
-	logrus.Debugf("Аккаунт %s: объем за 15 секунд = %s ETH", address, volume.String())
+	logrus.Debugf("Аккаунт %s: объем за период = %s", address, utils.FormatETH(volume))
 	return nil
 }
 
@@ -119,18 +130,73 @@ func (c *ActivityCalculator) CalculateTokenInteractions(address string, stats *m
 	return nil
 }
 
+// CalculateTokenVolumeMetrics считает объемы отслеживаемого токена аккаунта за все время
+func (c *ActivityCalculator) CalculateTokenVolumeMetrics(address string, stats *models.AccountStats) error {
+	if c.tokenAddress == (common.Address{}) {
+		return nil
+	}
+
+	volume, err := c.accountRepo.GetTokenVolume(address, c.tokenAddress.Hex(), time.Unix(0, 0), time.Now())
+	if err != nil {
+		return err
+	}
+
+	stats.TokenVolumeSent = models.NewBigInt(volume.Sent)
+	stats.TokenVolumeReceived = models.NewBigInt(volume.Received)
+
+	logrus.Debugf("Аккаунт %s: объем токена отправлено = %s, получено = %s",
+		address, volume.Sent.String(), volume.Received.String())
+	return nil
+}
+
+// CalculateCounterpartyMetrics считает различных контрагентов аккаунта за все время
+func (c *ActivityCalculator) CalculateCounterpartyMetrics(address string, stats *models.AccountStats) error {
+	counterparties, err := c.accountRepo.CountCounterparties(address)
+	if err != nil {
+		return err
+	}
+
+	stats.Counterparties = counterparties
+
+	logrus.Debugf("Аккаунт %s: различных контрагентов = %d", address, counterparties)
+	return nil
+}
+
+// CalculateGasMetrics пересчитывает перцентили цены газа по всем отправленным транзакциям аккаунта.
+// Суммарные комиссии и газ накапливаются атомарно при обработке транзакций
+func (c *ActivityCalculator) CalculateGasMetrics(address string, stats *models.AccountStats) error {
+	percentiles, err := c.accountRepo.GetGasPricePercentiles(address)
+	if err != nil {
+		return err
+	}
+
+	stats.GasPriceP50 = percentiles.P50
+	stats.GasPriceP90 = percentiles.P90
+
+	logrus.Debugf("Аккаунт %s: комиссии = %s, цена газа p50 = %s, p90 = %s wei",
+		address, utils.FormatETH(stats.TotalFeesPaid.Int()), percentiles.P50.String(), percentiles.P90.String())
+	return nil
+}
+
+// CalculateETHBalance берет баланс ETH из леджера - он соответствует последнему обработанному блоку,
+// а не текущему состоянию ноды
 func (c *ActivityCalculator) CalculateETHBalance(address string, stats *models.AccountStats) error {
-	if c.ethClient == nil {
+	var balance *big.Int
+	var err error
+	switch {
+	case c.balanceLedger != nil:
+		balance, err = c.balanceLedger.CurrentBalance(context.Background(), address)
+	case c.ethClient != nil:
+		balance, err = c.ethClient.GetBalance(context.Background(), common.HexToAddress(address))
+	default:
 		return fmt.Errorf("eth client not initialized")
 	}
-
-	balance, err := c.ethClient.GetBalance(context.Background(), common.HexToAddress(address))
 	if err != nil {
 		return fmt.Errorf("ошибка получения баланса ETH: %v", err)
 	}
 
-	stats.SetETHBalance(balance)
-	logrus.Debugf("Аккаунт %s: баланс ETH = %s wei", address, balance.String())
+	stats.ETHBalance = models.NewBigInt(balance)
+	logrus.Debugf("Аккаунт %s: баланс = %s", address, utils.FormatETH(balance))
 	return nil
 }
 
@@ -151,6 +217,21 @@ func (c *ActivityCalculator) CalculateAllMetrics(address string, stats *models.A
 		return err
 	}
 
+	if err := c.CalculateTokenVolumeMetrics(address, stats); err != nil {
+		logrus.Errorf("Ошибка расчета объемов токена для %s: %v", address, err)
+		return err
+	}
+
+	if err := c.CalculateCounterpartyMetrics(address, stats); err != nil {
+		logrus.Errorf("Ошибка подсчета контрагентов для %s: %v", address, err)
+		return err
+	}
+
+	if err := c.CalculateGasMetrics(address, stats); err != nil {
+		logrus.Errorf("Ошибка расчета метрик газа для %s: %v", address, err)
+		return err
+	}
+
 	// Добавляем расчет баланса ETH
 	if err := c.CalculateETHBalance(address, stats); err != nil {
 		logrus.Errorf("Ошибка получения баланса ETH для %s: %v", address, err)
@@ -162,7 +243,7 @@ func (c *ActivityCalculator) CalculateAllMetrics(address string, stats *models.A
 
 func (c *ActivityCalculator) ProcessAccountActivities() error {
 	// Получаем начало текущего периода
-	currentPeriod := time.Now().Truncate(15 * time.Second)
+	currentPeriod := utils.GetCurrentPeriodStart()
 
 	// Получаем все транзакции за текущий период
 	transactions, err := c.accountRepo.GetTransactionsSince(currentPeriod)
@@ -170,37 +251,12 @@ func (c *ActivityCalculator) ProcessAccountActivities() error {
 		return err
 	}
 
-	// Создаем map для хранения активности по адресам
-	activityMap := make(map[string]*models.AccountActivity)
-
-	// Создаем map для кэширования результатов проверки на контракт
-	contractCache := make(map[string]bool)
+	// Активность и контрагенты по адресам и периодам
+	acc := newActivityAccumulator()
 
 	// Обрабатываем каждую транзакцию
 	for _, tx := range transactions {
-
-		// Обрабатываем отправителя, проверяем, не является ли он контрактом, ведь нас интересуют только активность обычных аккаунтов
-		// В будущем можно будет легко переделать еще и на обработку контрактов, но сейчас это не нужно
-		isContract, exists := contractCache[tx.From]
-		if !exists {
-			isContract = c.isContract(tx.From)
-			contractCache[tx.From] = isContract
-		}
-
-		// Проверяем, является ли это обычной ETH транзакцией
-		if tx.To != c.tokenAddress.Hex() && !isContract {
-			// Это обычная ETH транзакция
-			fromActivity := getOrCreateActivity(activityMap, tx.From, currentPeriod)
-			fromActivity.TransactionCount++
-
-			if value, ok := big.NewInt(0).SetString(tx.Value, 10); ok {
-				currentVolume := fromActivity.GetVolumeETH()
-				fromActivity.SetVolumeETH(currentVolume.Add(currentVolume, value))
-			}
-		}
-
-		// Если транзакция к токен-контракту, она будет обработана как ERC20 трансфер позже
-
+		c.addTransactionActivity(acc, &tx, currentPeriod)
 	}
 
 	// Получаем все ERC20 трансферы за текущий период
@@ -210,26 +266,14 @@ func (c *ActivityCalculator) ProcessAccountActivities() error {
 	} else {
 		// Обрабатываем каждый ERC20 трансфер
 		for _, transfer := range erc20Transfers {
-
-			// Проверяем отправителя на контракт
-			// Если в будущем я реализую автомавызов транзакций контрактом, то это проверку нужно будет убрать
-			isContract, exists := contractCache[transfer.From]
-			if !exists {
-				isContract = c.isContract(transfer.From)
-				contractCache[transfer.From] = isContract
-			}
-
-			// Обрабатываем только отправителя и только если это не контракт
-			if !isContract {
-				fromActivity := getOrCreateActivity(activityMap, transfer.From, currentPeriod)
-				fromActivity.TokenTransfers++
-			}
+			c.addTransferActivity(acc, &transfer, currentPeriod)
 		}
 	}
 
 	// Сохраняем или обновляем все активности
-	for _, activity := range activityMap {
-		if err := c.accountRepo.SaveAccountActivity(activity); err != nil {
+	acc.finish()
+	for key, activity := range acc.activities {
+		if err := c.accountRepo.SaveAccountActivity(activity, acc.counterpartiesOf(key)); err != nil {
 			logrus.Errorf("Ошибка сохранения активности для %s: %v", activity.Address, err)
 		}
 	}
@@ -237,24 +281,242 @@ func (c *ActivityCalculator) ProcessAccountActivities() error {
 	return nil
 }
 
+// RecomputeActivities заново собирает активность аккаунтов за [from, to) из сохраненных транзакций и трансферов.
+// Границы выравниваются по базовым периодам; существующая активность за эти периоды заменяется, поэтому
+// пересчет можно безопасно повторять
+func (c *ActivityCalculator) RecomputeActivities(ctx context.Context, from, to time.Time) error {
+	period := utils.ActivityPeriod()
+	from = utils.PeriodStart(from)
+	if aligned := utils.PeriodStart(to); aligned.Before(to) {
+		to = aligned.Add(period)
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	total := 0
+	for chunkStart := from; chunkStart.Before(to); chunkStart = chunkStart.Add(recomputeChunkSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunkEnd := chunkStart.Add(recomputeChunkSize)
+		if chunkEnd.After(to) {
+			chunkEnd = to
+		}
+
+		count, err := c.recomputeChunk(chunkStart, chunkEnd)
+		if err != nil {
+			return fmt.Errorf("ошибка пересчета активности за %v - %v: %v", chunkStart, chunkEnd, err)
+		}
+		total += count
+	}
+
+	logrus.Infof("Пересчитана активность за %v - %v: %d записей", from, to, total)
+	return nil
+}
+
+// recomputeChunk пересчитывает активность за [from, to), границы которого совпадают с границами периодов
+func (c *ActivityCalculator) recomputeChunk(from, to time.Time) (int, error) {
+	transactions, err := c.accountRepo.GetTransactionsInRange(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	transfers, err := c.accountRepo.GetERC20TransfersInRange(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	acc := newActivityAccumulator()
+	for _, tx := range transactions {
+		c.addTransactionActivity(acc, &tx, utils.PeriodStart(tx.Timestamp))
+	}
+	for _, transfer := range transfers {
+		c.addTransferActivity(acc, &transfer, utils.PeriodStart(transfer.Timestamp))
+	}
+
+	acc.finish()
+	activities := make([]models.AccountActivity, 0, len(acc.activities))
+	var counterparties []models.AccountCounterparty
+	for key, activity := range acc.activities {
+		for _, counterparty := range acc.counterpartiesOf(key) {
+			counterparties = append(counterparties, models.AccountCounterparty{
+				Address:      activity.Address,
+				Period:       activity.Period,
+				Counterparty: counterparty,
+			})
+		}
+		activities = append(activities, *activity)
+	}
+
+	if err := c.accountRepo.ReplaceAccountActivities(from, to, activities, counterparties); err != nil {
+		return 0, err
+	}
+	return len(activities), nil
+}
+
+// isAccount проверяет, что адрес - обычный аккаунт, а не контракт (с кэшем на время одного прохода)
+func (c *ActivityCalculator) isAccount(acc *activityAccumulator, address string) bool {
+	isContract, exists := acc.contractCache[address]
+	if !exists {
+		isContract = c.isContract(address)
+		acc.contractCache[address] = isContract
+	}
+	return !isContract
+}
+
+// addTransactionActivity учитывает ETH транзакцию в исходящей активности отправителя и входящей активности получателя
+func (c *ActivityCalculator) addTransactionActivity(acc *activityAccumulator, tx *models.Transaction, period time.Time) {
+	// Комиссии отправитель платит за любую транзакцию, включая вызовы токен-контракта
+	if c.isAccount(acc, tx.From) {
+		acc.addFee(tx.From, period, tx)
+	}
+
+	// Учитываем только обычные аккаунты, контракты нас не интересуют
+	// В будущем можно будет легко переделать еще и на обработку контрактов, но сейчас это не нужно
+	// Транзакция к токен-контракту будет обработана как ERC20 трансфер
+	if tx.To == c.tokenAddress.Hex() {
+		return
+	}
+
+	if c.isAccount(acc, tx.From) {
+		fromActivity := acc.get(tx.From, period)
+		fromActivity.TransactionCount++
+		fromActivity.VolumeETH = fromActivity.VolumeETH.Add(tx.TransferredValue().Int())
+		acc.addCounterparty(tx.From, period, tx.To)
+	}
+
+	// Транзакция деплоя без получателя: созданный контракт не учитываем
+	if tx.To != "" && c.isAccount(acc, tx.To) {
+		toActivity := acc.get(tx.To, period)
+		toActivity.ReceivedCount++
+		toActivity.VolumeETHReceived = toActivity.VolumeETHReceived.Add(tx.TransferredValue().Int())
+		acc.addCounterparty(tx.To, period, tx.From)
+	}
+}
+
+// addTransferActivity учитывает ERC20 трансфер в исходящей активности отправителя и входящей активности получателя
+func (c *ActivityCalculator) addTransferActivity(acc *activityAccumulator, transfer *models.ERC20Transfer, period time.Time) {
+	// Отправитель учитывается, если это не контракт и не mint
+	// Если в будущем я реализую автомавызов транзакций контрактом, то это проверку нужно будет убрать
+	if transfer.Kind != models.TransferKindMint && c.isAccount(acc, transfer.From) {
+		fromActivity := acc.get(transfer.From, period)
+		fromActivity.TokenTransfers++
+		fromActivity.TokenVolume = fromActivity.TokenVolume.Add(transfer.Value.Int())
+		acc.addCounterparty(transfer.From, period, transfer.To)
+	}
+
+	// Получатель учитывается, если это не контракт и не burn
+	if transfer.Kind != models.TransferKindBurn && c.isAccount(acc, transfer.To) {
+		toActivity := acc.get(transfer.To, period)
+		toActivity.TokenTransfersReceived++
+		toActivity.TokenVolumeReceived = toActivity.TokenVolumeReceived.Add(transfer.Value.Int())
+		acc.addCounterparty(transfer.To, period, transfer.From)
+	}
+}
+
 func (c *ActivityCalculator) getERC20TransfersSince(since time.Time) ([]models.ERC20Transfer, error) {
-	nextPeriod := since.Add(15 * time.Second)
+	nextPeriod := since.Add(utils.ActivityPeriod())
 	var transfers []models.ERC20Transfer
 	// Для наносекундной точности
-	err := repositories.DB.Where("created_at >= ? AND created_at < ?",
+	err := repositories.DB.Where("timestamp >= ? AND timestamp < ?",
 		since.Truncate(time.Microsecond),
 		nextPeriod.Truncate(time.Microsecond)).Find(&transfers).Error
 	return transfers, err
 }
 
-func getOrCreateActivity(activityMap map[string]*models.AccountActivity, address string, period time.Time) *models.AccountActivity {
-	activity, exists := activityMap[address]
+// activityKey - ключ активности аккаунта за период (время хранится в секундах, чтобы не зависеть от часового пояса)
+type activityKey struct {
+	address string
+	period  int64
+}
+
+// activityAccumulator собирает активность аккаунтов и их контрагентов по периодам
+type activityAccumulator struct {
+	activities     map[activityKey]*models.AccountActivity
+	counterparties map[activityKey]map[string]struct{}
+	gasPrices      map[activityKey][]*big.Int
+	contractCache  map[string]bool
+}
+
+func newActivityAccumulator() *activityAccumulator {
+	return &activityAccumulator{
+		activities:     make(map[activityKey]*models.AccountActivity),
+		counterparties: make(map[activityKey]map[string]struct{}),
+		gasPrices:      make(map[activityKey][]*big.Int),
+		contractCache:  make(map[string]bool),
+	}
+}
+
+// addFee учитывает комиссию отправленной транзакции в активности отправителя
+func (acc *activityAccumulator) addFee(address string, period time.Time, tx *models.Transaction) {
+	activity := acc.get(address, period)
+	activity.FeesPaid = activity.FeesPaid.Add(tx.GetTotalGasCost())
+	activity.GasUsed += tx.GasUsed
+
+	key := activityKey{address: address, period: period.Unix()}
+	acc.gasPrices[key] = append(acc.gasPrices[key], tx.GasPrice.Int())
+}
+
+// finish заполняет метрики, которые считаются по всем данным периода: число контрагентов и перцентили цены газа
+func (acc *activityAccumulator) finish() {
+	for key, activity := range acc.activities {
+		activity.Counterparties = uint32(len(acc.counterparties[key]))
+		activity.GasPriceP50 = models.NewBigInt(percentileDisc(acc.gasPrices[key], 0.5))
+		activity.GasPriceP90 = models.NewBigInt(percentileDisc(acc.gasPrices[key], 0.9))
+	}
+}
+
+// percentileDisc возвращает перцентиль по методу ближайшего ранга, как percentile_disc в PostgreSQL
+func percentileDisc(values []*big.Int, fraction float64) *big.Int {
+	if len(values) == 0 {
+		return big.NewInt(0)
+	}
+
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	rank := int(math.Ceil(fraction*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// get возвращает активность аккаунта за период, создавая ее при первом обращении
+func (acc *activityAccumulator) get(address string, period time.Time) *models.AccountActivity {
+	key := activityKey{address: address, period: period.Unix()}
+	activity, exists := acc.activities[key]
 	if !exists {
 		activity = &models.AccountActivity{
 			Address: address,
 			Period:  period,
 		}
-		activityMap[address] = activity
+		acc.activities[key] = activity
 	}
 	return activity
 }
+
+// addCounterparty запоминает контрагента аккаунта в периоде; нулевой адрес (mint/burn) контрагентом не считается
+func (acc *activityAccumulator) addCounterparty(address string, period time.Time, counterparty string) {
+	if counterparty == models.ZeroAddress || counterparty == "" {
+		return
+	}
+
+	key := activityKey{address: address, period: period.Unix()}
+	if acc.counterparties[key] == nil {
+		acc.counterparties[key] = make(map[string]struct{})
+	}
+	acc.counterparties[key][counterparty] = struct{}{}
+}
+
+// counterpartiesOf возвращает контрагентов аккаунта в периоде
+func (acc *activityAccumulator) counterpartiesOf(key activityKey) []string {
+	counterparties := make([]string, 0, len(acc.counterparties[key]))
+	for counterparty := range acc.counterparties[key] {
+		counterparties = append(counterparties, counterparty)
+	}
+	return counterparties
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+)
+
+func bigInts(values ...int64) []*big.Int {
+	result := make([]*big.Int, len(values))
+	for i, value := range values {
+		result[i] = big.NewInt(value)
+	}
+	return result
+}
+
+// Ожидаемые значения совпадают с percentile_disc в PostgreSQL:
+// первое значение, позиция которого (ceil(fraction * n)) достигает доли fraction
+func TestPercentileDisc(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []*big.Int
+		fraction float64
+		want     int64
+	}{
+		{"пустой набор", bigInts(), 0.5, 0},
+		{"одно значение, медиана", bigInts(7), 0.5, 7},
+		{"одно значение, p90", bigInts(7), 0.9, 7},
+		{"нулевая доля - минимум", bigInts(3, 1, 2), 0, 1},
+		{"доля 1 - максимум", bigInts(3, 1, 2), 1, 3},
+		{"медиана нечетного числа", bigInts(5, 1, 4, 2, 3), 0.5, 3},
+		{"медиана четного числа - нижняя из средних", bigInts(4, 1, 3, 2), 0.5, 2},
+		{"ровно на границе ранга", bigInts(10, 20, 30, 40, 50, 60, 70, 80, 90, 100), 0.9, 90},
+		{"чуть выше границы ранга", bigInts(10, 20, 30, 40, 50, 60, 70, 80, 90, 100), 0.91, 100},
+		{"p90 по трем значениям", bigInts(1, 2, 3), 0.9, 3},
+		{"повторяющиеся значения", bigInts(5, 5, 1, 5), 0.25, 1},
+		{"отрицательные значения", bigInts(-3, -1, -2), 0.5, -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentileDisc(tt.values, tt.fraction); got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Errorf("percentileDisc(%v, %v) = %s, ожидалось %d", tt.values, tt.fraction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentileDiscKeepsOrder(t *testing.T) {
+	values := bigInts(3, 1, 2)
+	percentileDisc(values, 0.5)
+
+	for i, want := range []int64{3, 1, 2} {
+		if values[i].Int64() != want {
+			t.Fatalf("percentileDisc изменил порядок входных значений: %v", values)
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"backend/configs"
 	"backend/internal/models"
 	"backend/internal/repositories"
+	"backend/internal/utils"
+	"backend/pkg/contracts"
 	"backend/pkg/ethereum"
 	"backend/pkg/listeners"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -15,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const (
@@ -28,8 +32,21 @@ type Analyzer struct {
 	activityCalculator *ActivityCalculator
 	contractAnalyzer   *ContractAnalyzer
 	notifier           *Notifier
+	tokenRegistry      *TokenRegistry
+	txManager          *ethereum.TxManager
+	relayer            *Relayer
+	statsReconciler    *StatsReconciler
+	balanceReconciler  *BalanceReconciler
+	supplyTracker      *SupplyTracker
+	balanceLedger      *BalanceLedger
+	holderDistribution *HolderDistribution
+	graphRepo          *repositories.GraphRepository
 	config             *configs.Config
 	tokenAddress       common.Address
+
+	// Интервал прошедших периодов, затронутых проиндексированными старыми блоками (для пересчета активности)
+	staleFrom time.Time
+	staleTo   time.Time
 }
 
 func NewAnalyzer(cfg *configs.Config) (*Analyzer, error) {
@@ -44,11 +61,27 @@ func NewAnalyzer(cfg *configs.Config) (*Analyzer, error) {
 	// Создаем репозиторий
 	accountRepo := repositories.NewAccountRepository()
 
+	// Создаем реестр метаданных токенов
+	tokenRegistry, err := NewTokenRegistry(ethClient, repositories.NewTokenRepository())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания реестра токенов: %v", err)
+	}
+
 	// Создаем калькулятор активности
 	activityCalculator := NewActivityCalculator(accountRepo, ethClient, tokenAddress)
 
+	// Создаем репозиторий графа взаимодействий адресов
+	graphRepo := repositories.NewGraphRepository()
+
 	// Создаем анализатор контрактов
-	contractAnalyzer := NewContractAnalyzer(ethClient, accountRepo, tokenAddress)
+	contractAnalyzer := NewContractAnalyzer(ethClient, accountRepo, repositories.NewSignatureRepository(), graphRepo, tokenRegistry, tokenAddress)
+
+	// Импортируем сигнатуры методов из файла, если он указан
+	if cfg.Ethereum.SignaturesFile != "" {
+		if err := contractAnalyzer.ImportSignatureFile(cfg.Ethereum.SignaturesFile); err != nil {
+			logrus.Errorf("Ошибка импорта сигнатур методов: %v", err)
+		}
+	}
 
 	// Создаем нотификатор
 	notifier, err := NewNotifier(cfg)
@@ -56,12 +89,58 @@ func NewAnalyzer(cfg *configs.Config) (*Analyzer, error) {
 		return nil, fmt.Errorf("ошибка создания нотификатора: %v", err)
 	}
 
+	// Ретранслятор внешних транзакций включается только при заданных адресах
+	var txManager *ethereum.TxManager
+	var relayer *Relayer
+	if len(cfg.Relayer.Addresses) > 0 {
+		txManager, err = ethereum.NewTxManager(ethClient)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания менеджера транзакций: %v", err)
+		}
+
+		relayer, err = NewRelayer(ethClient, txManager, repositories.NewRelayRepository(), tokenAddress, cfg.Relayer.Addresses, cfg.Relayer.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания ретранслятора: %v", err)
+		}
+	}
+
+	// Создаем сверку статистики с контрактом
+	statsReconciler, err := NewStatsReconciler(ethClient, repositories.NewReconciliationRepository(), notifier, tokenRegistry, tokenAddress, cfg.Reconciler.DriftThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания сверки статистики: %v", err)
+	}
+
+	// Создаем сверку балансов токена с balanceOf
+	balanceReconciler := NewBalanceReconciler(ethClient, repositories.NewReconciliationRepository(), notifier, tokenRegistry, tokenAddress)
+
+	// Создаем историю эмиссии токенов
+	supplyTracker := NewSupplyTracker(ethClient, repositories.NewSupplyRepository(), notifier, tokenRegistry)
+
+	// Создаем леджер истории балансов
+	balanceLedger := NewBalanceLedger(ethClient, repositories.NewBalanceHistoryRepository(), repositories.NewReconciliationRepository(), notifier, cfg.Ethereum.TraceInternalTransfers)
+	activityCalculator.SetBalanceLedger(balanceLedger)
+
+	// Создаем снимки распределения держателей токена
+	holderDistribution := NewHolderDistribution(repositories.NewHolderRepository(), repositories.NewReconciliationRepository(), tokenAddress)
+
+	accountAnalyzer := NewAccountAnalyzer(accountRepo, ethClient, tokenAddress)
+	accountAnalyzer.GetTokenTracker().SetTokenRegistry(tokenRegistry)
+
 	return &Analyzer{
 		ethClient:          ethClient,
-		accountAnalyzer:    NewAccountAnalyzer(accountRepo, ethClient, tokenAddress),
+		accountAnalyzer:    accountAnalyzer,
 		activityCalculator: activityCalculator,
 		contractAnalyzer:   contractAnalyzer,
 		notifier:           notifier,
+		tokenRegistry:      tokenRegistry,
+		txManager:          txManager,
+		relayer:            relayer,
+		statsReconciler:    statsReconciler,
+		balanceReconciler:  balanceReconciler,
+		supplyTracker:      supplyTracker,
+		balanceLedger:      balanceLedger,
+		holderDistribution: holderDistribution,
+		graphRepo:          graphRepo,
 		config:             cfg,
 		tokenAddress:       tokenAddress,
 	}, nil
@@ -70,6 +149,11 @@ func NewAnalyzer(cfg *configs.Config) (*Analyzer, error) {
 func (a *Analyzer) Start(ctx context.Context) error {
 	logrus.Info("Запуск анализатора блокчейн активности...")
 
+	// Строим граф взаимодействий из уже проиндексированных данных при первом запуске
+	if err := a.buildInteractionGraph(); err != nil {
+		logrus.Errorf("Ошибка построения графа взаимодействий: %v", err)
+	}
+
 	// Создаем event listener для указанного контракта
 	eventListener, err := listeners.NewEventListener(a.ethClient.GetClient(), a.tokenAddress)
 	if err != nil {
@@ -77,6 +161,25 @@ func (a *Analyzer) Start(ctx context.Context) error {
 	}
 	a.eventListener = eventListener
 
+	listenerConfig := listeners.ListenerConfig{
+		Mode:       a.config.Listener.Mode,
+		WSEndpoint: a.config.Ethereum.WSEndpoint,
+		FromFilter: hexAddresses(a.config.Listener.FromAddresses),
+		ToFilter:   hexAddresses(a.config.Listener.ToAddresses),
+	}
+
+	// Дополнительно дублируем события в NDJSON файл, если он указан
+	if a.config.Listener.EventsFile != "" {
+		fileSink, err := listeners.NewJSONFileSink(a.config.Listener.EventsFile)
+		if err != nil {
+			return err
+		}
+		fileSink.SetAmountFormatter(a.tokenRegistry.FormatAmount)
+		listenerConfig.Sink = listeners.NewMultiSink(listeners.NewDBSink(repositories.DB), fileSink)
+	}
+
+	a.eventListener.SetConfig(listenerConfig)
+
 	// Запускаем прослушивание событий
 	if err := a.eventListener.StartListening(ctx); err != nil {
 		return err
@@ -88,6 +191,46 @@ func (a *Analyzer) Start(ctx context.Context) error {
 	// Запускаем обработку активности аккаунтов
 	go a.startActivityProcessing(ctx)
 
+	// Запускаем обновление метаданных токенов
+	if err := a.tokenRegistry.Start(ctx); err != nil {
+		return fmt.Errorf("ошибка запуска реестра токенов: %v", err)
+	}
+
+	// Запускаем ретранслятор внешних транзакций
+	if a.relayer != nil {
+		if err := a.txManager.Start(ctx); err != nil {
+			return fmt.Errorf("ошибка запуска менеджера транзакций: %v", err)
+		}
+		if err := a.relayer.Start(ctx); err != nil {
+			return fmt.Errorf("ошибка запуска ретранслятора: %v", err)
+		}
+	}
+
+	// Запускаем периодическую сверку статистики с контрактом
+	if err := a.statsReconciler.Start(ctx); err != nil {
+		return fmt.Errorf("ошибка запуска сверки статистики: %v", err)
+	}
+
+	// Запускаем периодическую сверку балансов токена
+	if err := a.balanceReconciler.Start(ctx); err != nil {
+		return fmt.Errorf("ошибка запуска сверки балансов: %v", err)
+	}
+
+	// Запускаем периодическую сверку леджера балансов ETH
+	if err := a.balanceLedger.Start(ctx); err != nil {
+		return fmt.Errorf("ошибка запуска сверки леджера балансов: %v", err)
+	}
+
+	// Запускаем снимки распределения держателей
+	if err := a.holderDistribution.Start(ctx); err != nil {
+		return fmt.Errorf("ошибка запуска снимков распределения держателей: %v", err)
+	}
+
+	// Запускаем ведение истории эмиссии
+	if err := a.supplyTracker.Start(ctx); err != nil {
+		return fmt.Errorf("ошибка запуска истории эмиссии: %v", err)
+	}
+
 	// Запускаем нотификатор
 	if err := a.notifier.Start(ctx); err != nil {
 		return fmt.Errorf("ошибка запуска нотификатора: %v", err)
@@ -97,6 +240,23 @@ func (a *Analyzer) Start(ctx context.Context) error {
 	return nil
 }
 
+// buildInteractionGraph заполняет граф взаимодействий по сохраненным транзакциям и трансферам, если он еще пуст
+func (a *Analyzer) buildInteractionGraph() error {
+	empty, err := a.graphRepo.IsEmpty()
+	if err != nil || !empty {
+		return err
+	}
+
+	edges, err := a.graphRepo.Rebuild()
+	if err != nil {
+		return err
+	}
+	if edges > 0 {
+		logrus.Infof("Граф взаимодействий построен по сохраненным данным: %d ребер", edges)
+	}
+	return nil
+}
+
 func (a *Analyzer) startTransactionMonitoring(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
@@ -168,6 +328,9 @@ func (a *Analyzer) processBlockRange(ctx context.Context) error {
 
 	logrus.Infof("Нужно обработать %d блоков (с #%d по #%d)", blocksToProcess, lastProcessedBlock+1, lastProcessedBlock+blocksToProcess)
 
+	// Активность за прошедшие периоды, в которые попали обработанные блоки, пересчитываем даже при ошибке
+	defer a.recomputeStaleActivity(ctx)
+
 	// Обрабатываем каждый блок в диапазоне
 	for blockNum := lastProcessedBlock + 1; blockNum <= lastProcessedBlock+blocksToProcess; blockNum++ {
 		select {
@@ -191,19 +354,14 @@ func (a *Analyzer) processBlockRange(ctx context.Context) error {
 	return nil
 }
 
-func (a *Analyzer) processTransaction(ctx context.Context, tx *types.Transaction, blockTime uint64) error {
+// processTransaction сохраняет транзакцию и возвращает ее receipt (для учета комиссий блока)
+func (a *Analyzer) processTransaction(ctx context.Context, tx *types.Transaction, blockTime uint64) (*types.Receipt, error) {
 	client := a.ethClient.GetClient()
 
 	// Получаем receipt для статуса транзакции
 	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
 	if err != nil {
-		return err
-	}
-
-	// Проверяем статус транзакции
-	if receipt.Status == 0 {
-		logrus.Debugf("Транзакция %s была revert", tx.Hash().Hex())
-		return nil // Пропускаем revert транзакции
+		return nil, err
 	}
 
 	// Получаем информацию об отправителе
@@ -218,7 +376,7 @@ func (a *Analyzer) processTransaction(ctx context.Context, tx *types.Transaction
 
 	from, err := types.Sender(signer, tx)
 	if err != nil {
-		return err
+		return receipt, err
 	}
 
 	var to string
@@ -232,61 +390,85 @@ func (a *Analyzer) processTransaction(ctx context.Context, tx *types.Transaction
 		BlockNumber: receipt.BlockNumber.Uint64(),
 		From:        from.Hex(),
 		To:          to,
-		Value:       tx.Value().String(),
+		Value:       models.NewBigInt(tx.Value()),
 		GasUsed:     receipt.GasUsed,
 		Status:      uint64(receipt.Status),
 		Timestamp:   time.Unix(int64(blockTime), 0),
+
+		ContractCall: to != "" && len(tx.Data()) > 0,
+	}
+	if to == "" {
+		transaction.ContractAddress = receipt.ContractAddress.Hex()
 	}
 
-	// Обрабатываем GasPrice для разных типов транзакций
-	if tx.GasPrice() != nil {
-		transaction.SetGasPrice(tx.GasPrice())
+	// Фактически уплаченная цена газа - EffectiveGasPrice из receipt.
+	// tx.GasPrice() у EIP-1559 транзакций возвращает GasFeeCap, то есть верхнюю границу
+	if receipt.EffectiveGasPrice != nil {
+		transaction.GasPrice = models.NewBigInt(receipt.EffectiveGasPrice)
 	} else {
-		// Для EIP-1559 транзакций используем EffectiveGasPrice из receipt
-		if receipt.EffectiveGasPrice != nil {
-			transaction.SetGasPrice(receipt.EffectiveGasPrice)
-		} else {
-			transaction.SetGasPrice(big.NewInt(0))
-		}
+		transaction.GasPrice = models.NewBigInt(tx.GasPrice())
 	}
 
-	// Проверяем, существует ли уже такая транзакция
-	var existingTx models.Transaction
-	result := repositories.DB.Where("hash = ?", transaction.Hash).First(&existingTx)
-	if result.Error == nil {
-		// Транзакция уже существует
-		return nil
+	// Revert транзакции тоже сохраняются (status = 0): комиссию и газ они списывают, а в объемах не учитываются
+	if !transaction.IsSuccessful() {
+		logrus.Debugf("Транзакция %s была revert", transaction.Hash)
 	}
 
-	// Сохраняем новую транзакцию с обработкой дублирования
-	result = repositories.DB.Create(transaction)
+	// Сохраняем новую транзакцию; уникальный индекс по hash отсекает повторную обработку
+	result := repositories.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(transaction)
 	if result.Error != nil {
-		// Если ошибка дублирования, просто игнорируем
-		if result.Error.Error() == "duplicate key value violates unique constraint \"idx_transactions_hash\"" {
-			logrus.Debugf("Транзакция %s уже существует, пропускаем", transaction.Hash)
-			return nil
+		return receipt, result.Error
+	}
+	if result.RowsAffected == 0 {
+		logrus.Debugf("Транзакция %s уже существует, пропускаем", transaction.Hash)
+		return receipt, nil
+	}
+
+	// Внутренние переводы ETH возможны только при успешном вызове контракта или его создании
+	var internal []ethereum.InternalTransfer
+	if transaction.IsSuccessful() && len(tx.Data()) > 0 {
+		internal, err = a.balanceLedger.InternalTransfers(ctx, tx.Hash())
+		if err != nil {
+			logrus.Errorf("Ошибка трассировки транзакции %s: %v", transaction.Hash, err)
 		}
-		return result.Error
 	}
 
-	// Обновляем статистику аккаунтов только если это не транзакция деплоя контракта
-	if to != "" {
-		if err := a.accountAnalyzer.UpdateAccountStats(transaction); err != nil {
-			logrus.Errorf("Ошибка обновления статистики аккаунтов для транзакции %s: %v", transaction.Hash, err)
-			// Не возвращаем ошибку, чтобы не прерывать обработку транзакций
+	// Записываем изменения балансов ETH в леджер
+	if err := a.balanceLedger.RecordTransaction(ctx, transaction, receipt.TransactionIndex, internal); err != nil {
+		logrus.Errorf("Ошибка записи истории балансов ETH для транзакции %s: %v", transaction.Hash, err)
+	}
+
+	// Ставим транзакцию в очередь ретрансляции в контракт
+	if a.relayer != nil {
+		if err := a.relayer.Enqueue(transaction, tx.Data()); err != nil {
+			logrus.Errorf("Ошибка ретрансляции транзакции %s: %v", transaction.Hash, err)
 		}
-	} else {
-		logrus.Debugf("Пропускаем обновление статистики для транзакции деплоя контракта: %s", transaction.Hash)
+	}
+
+	// Обновляем статистику аккаунтов; у транзакции деплоя учитывается только отправитель (газ деплоя)
+	if err := a.accountAnalyzer.UpdateAccountStats(transaction); err != nil {
+		logrus.Errorf("Ошибка обновления статистики аккаунтов для транзакции %s: %v", transaction.Hash, err)
+		// Не возвращаем ошибку, чтобы не прерывать обработку транзакций
+	}
+
+	// Revert транзакция ничего не перевела: в граф и логи не попадает
+	if !transaction.IsSuccessful() {
+		return receipt, nil
+	}
+
+	// Учитываем перевод ETH в графе взаимодействий
+	if err := a.graphRepo.RecordEdge(transaction.From, to, models.EdgeAssetETH, transaction.Value.Int(), transaction.Timestamp); err != nil {
+		logrus.Errorf("Ошибка обновления графа взаимодействий для транзакции %s: %v", transaction.Hash, err)
 	}
 
 	// Обрабатываем ERC20 события в логах транзакции
-	if err := a.processTransactionLogs(ctx, receipt); err != nil {
+	if err := a.processTransactionLogs(ctx, receipt, transaction.Timestamp); err != nil {
 		logrus.Errorf("Ошибка обработки логов транзакции %s: %v", transaction.Hash, err)
 		// Не возвращаем ошибку, чтобы не прерывать обработку транзакций
 	}
 
 	logrus.Debugf("Сохранена транзакция: %s", transaction.Hash)
-	return nil
+	return receipt, nil
 }
 
 func (a *Analyzer) processBlock(ctx context.Context, blockNum uint64) error {
@@ -304,9 +486,16 @@ func (a *Analyzer) processBlock(ctx context.Context, blockNum uint64) error {
 	processedCount := 0
 	errorCount := 0
 
+	// Чаевые получателю комиссий блока
+	priorityFees := big.NewInt(0)
+
 	// Обрабатываем каждую транзакцию в блоке
 	for _, tx := range block.Transactions() {
-		if err := a.processTransaction(ctx, tx, block.Time()); err != nil {
+		receipt, err := a.processTransaction(ctx, tx, block.Time())
+		if receipt != nil {
+			priorityFees.Add(priorityFees, PriorityFee(receipt, block.BaseFee()))
+		}
+		if err != nil {
 			logrus.Errorf("Ошибка обработки транзакции %s: %v", tx.Hash().Hex(), err)
 			errorCount++
 		} else {
@@ -314,12 +503,52 @@ func (a *Analyzer) processBlock(ctx context.Context, blockNum uint64) error {
 		}
 	}
 
+	// Записываем в леджер изменения балансов ETH уровня блока
+	if err := a.balanceLedger.RecordBlock(ctx, block, priorityFees); err != nil {
+		logrus.Errorf("Ошибка записи истории балансов ETH для блока #%d: %v", blockNum, err)
+	}
+
+	a.markStaleActivity(time.Unix(int64(block.Time()), 0))
+
 	logrus.Debugf("Блок #%d обработан: %d транзакций успешно, %d ошибок",
 		blockNum, processedCount, errorCount)
 
 	return nil
 }
 
+// markStaleActivity запоминает период блока, если он уже закончился: текущий период обрабатывает
+// ActivityCalculator.ProcessAccountActivities, а прошедшие нужно пересчитать из БД
+func (a *Analyzer) markStaleActivity(blockTime time.Time) {
+	if !blockTime.Before(utils.GetCurrentPeriodStart()) {
+		return
+	}
+
+	if a.staleFrom.IsZero() || blockTime.Before(a.staleFrom) {
+		a.staleFrom = blockTime
+	}
+	if blockTime.After(a.staleTo) {
+		a.staleTo = blockTime
+	}
+}
+
+// recomputeStaleActivity пересчитывает активность за прошедшие периоды, затронутые обработанными блоками
+func (a *Analyzer) recomputeStaleActivity(ctx context.Context) {
+	if a.staleFrom.IsZero() {
+		return
+	}
+	from, to := a.staleFrom, a.staleTo.Add(utils.ActivityPeriod())
+	a.staleFrom, a.staleTo = time.Time{}, time.Time{}
+
+	if err := a.activityCalculator.RecomputeActivities(ctx, from, to); err != nil {
+		logrus.Errorf("Ошибка пересчета активности за %v - %v: %v", from, to, err)
+	}
+}
+
+// RecomputeActivities пересчитывает активность аккаунтов за [from, to) из уже проиндексированных данных
+func (a *Analyzer) RecomputeActivities(ctx context.Context, from, to time.Time) error {
+	return a.activityCalculator.RecomputeActivities(ctx, from, to)
+}
+
 func (a *Analyzer) getLastProcessedBlock() (uint64, error) {
 	var state models.AnalyzerState
 
@@ -375,14 +604,49 @@ func (a *Analyzer) Stop() {
 		a.notifier.Stop()
 	}
 
+	if a.tokenRegistry != nil {
+		a.tokenRegistry.Stop()
+	}
+
+	if a.relayer != nil {
+		a.relayer.Stop()
+		a.txManager.Stop()
+	}
+
+	if a.statsReconciler != nil {
+		a.statsReconciler.Stop()
+	}
+
+	if a.balanceReconciler != nil {
+		a.balanceReconciler.Stop()
+	}
+
+	if a.supplyTracker != nil {
+		a.supplyTracker.Stop()
+	}
+
+	if a.balanceLedger != nil {
+		a.balanceLedger.Stop()
+	}
+
+	if a.holderDistribution != nil {
+		a.holderDistribution.Stop()
+	}
+
 	logrus.Info("Анализатор остановлен")
 }
 
-func (a *Analyzer) processTransactionLogs(ctx context.Context, receipt *types.Receipt) error {
+func (a *Analyzer) processTransactionLogs(ctx context.Context, receipt *types.Receipt, blockTime time.Time) error {
 	// Обрабатываем каждый лог в транзакции
-	for _, log := range receipt.Logs {
+	for i, log := range receipt.Logs {
+		// Следующий лог нужен, чтобы понять, учел ли перевод AnalyzerToken (TransactionRecorded)
+		var next *types.Log
+		if i+1 < len(receipt.Logs) {
+			next = receipt.Logs[i+1]
+		}
+
 		// Проверяем, является ли лог ERC20 Transfer событием
-		if err := a.processERC20TransferLog(log); err != nil {
+		if err := a.processERC20TransferLog(ctx, log, next, blockTime); err != nil {
 			logrus.Errorf("Ошибка обработки ERC20 лога: %v", err)
 			continue
 		}
@@ -390,159 +654,128 @@ func (a *Analyzer) processTransactionLogs(ctx context.Context, receipt *types.Re
 	return nil
 }
 
-func (a *Analyzer) processERC20TransferLog(log *types.Log) error {
-	return repositories.DB.Transaction(func(tx *gorm.DB) error {
-		// Проверяем, что это Transfer событие (должно быть 3 топика)
-		if len(log.Topics) < 3 {
-			return nil // Не Transfer событие
-		}
-
-		// Проверяем сигнатуру Transfer события
-		// keccak256("Transfer(address,address,uint256)") = 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
-		transferSignature := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
-		if log.Topics[0] != transferSignature {
-			return nil // Не Transfer событие
-		}
-
-		// Парсим адреса из топиков
-		from := common.BytesToAddress(log.Topics[1].Bytes())
-		to := common.BytesToAddress(log.Topics[2].Bytes())
-
-		// Проверяем на нулевой адрес
-		zeroAddress := common.HexToAddress("0x0000000000000000000000000000000000000000")
-		if from == zeroAddress || to == zeroAddress {
-
-			return nil
-		}
-
-		// Парсим значение из данных
-		value := big.NewInt(0)
-		if len(log.Data) > 0 {
-			value.SetBytes(log.Data)
-		}
-
-		// Создаем запись ERC20 трансфера
-		erc20Transfer := &models.ERC20Transfer{
-			TransactionHash: log.TxHash.Hex(),
-			ContractAddress: log.Address.Hex(),
-			From:            from.Hex(),
-			To:              to.Hex(),
-			Value:           value.String(),
-			BlockNumber:     log.BlockNumber,
-		}
+func (a *Analyzer) processERC20TransferLog(ctx context.Context, log, next *types.Log, blockTime time.Time) error {
+	// Проверяем, что это Transfer событие (должно быть 3 топика)
+	if len(log.Topics) < 3 {
+		return nil // Не Transfer событие
+	}
 
-		// Проверяем, существует ли уже такой трансфер
-		var existingTransfer models.ERC20Transfer
-		result := tx.Where("transaction_hash = ? AND contract_address = ? AND \"from\" = ? AND \"to\" = ?",
-			erc20Transfer.TransactionHash, erc20Transfer.ContractAddress, erc20Transfer.From, erc20Transfer.To).First(&existingTransfer)
-		if result.Error == nil {
-			// Трансфер уже существует
-			return nil
-		}
+	// Проверяем сигнатуру Transfer события
+	// keccak256("Transfer(address,address,uint256)") = 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+	transferSignature := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	if log.Topics[0] != transferSignature {
+		return nil // Не Transfer событие
+	}
 
-		// Сохраняем новый трансфер с обработкой дублирования
-		result = tx.Create(erc20Transfer)
-		if result.Error != nil {
-			// Если ошибка дублирования, просто игнорируем
-			if result.Error.Error() == "duplicate key value violates unique constraint" {
-				logrus.Debugf("ERC20 трансфер уже существует, пропускаем")
-				return nil
-			}
-			return result.Error
-		}
+	// Парсим адреса из топиков
+	from := common.BytesToAddress(log.Topics[1].Bytes())
+	to := common.BytesToAddress(log.Topics[2].Bytes())
 
-		// ИСПРАВЛЕНО: Обновляем статистику ERC20 транзакций только для новых уникальных транзакций
-		if err := a.updateERC20StatsForUniqueTransaction(erc20Transfer); err != nil {
-			logrus.Errorf("Ошибка обновления ERC20 статистики: %v", err)
-		}
+	// Парсим значение из данных
+	value := big.NewInt(0)
+	if len(log.Data) > 0 {
+		value.SetBytes(log.Data)
+	}
 
-		// Обновляем балансы токенов
-		if err := a.updateTokenBalances(erc20Transfer); err != nil {
-			logrus.Errorf("Ошибка обновления балансов токенов: %v", err)
-		}
+	// Создаем запись ERC20 трансфера
+	erc20Transfer := &models.ERC20Transfer{
+		TransactionHash: log.TxHash.Hex(),
+		LogIndex:        log.Index,
+		ContractAddress: log.Address.Hex(),
+		From:            from.Hex(),
+		To:              to.Hex(),
+		Value:           models.NewBigInt(value),
+		Kind:            models.TransferKindOf(from.Hex(), to.Hex()),
+		BlockNumber:     log.BlockNumber,
+		Timestamp:       blockTime,
+		Recorded:        contracts.IsRecordedTransfer(log, next),
+	}
 
-		logrus.Debugf("Сохранен ERC20 трансфер: %s от %s к %s значение %s",
-			erc20Transfer.ContractAddress, erc20Transfer.From, erc20Transfer.To, erc20Transfer.Value)
+	// Леджер идемпотентен, поэтому пишем в него и трансферы, уже сохраненные слушателем событий
+	if err := a.balanceLedger.RecordTransfer(ctx, erc20Transfer, log.TxIndex, log.Index, blockTime); err != nil {
+		logrus.Errorf("Ошибка записи истории балансов токена %s: %v", erc20Transfer.ContractAddress, err)
+	}
 
-		return nil
+	// Сохраняем трансфер и забираем его учет; трансфер, сохраненный слушателем событий, еще не учтен.
+	// Транзакция БД покрывает только сохранение: побочные эффекты и обращения к ноде идут после коммита
+	var accounted bool
+	err := repositories.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		accounted, err = repositories.SaveTransferForAccounting(tx, erc20Transfer)
+		return err
 	})
-}
-
-// ИСПРАВЛЕНО: Новая функция для обновления статистики по уникальным транзакциям
-func (a *Analyzer) updateERC20StatsForUniqueTransaction(transfer *models.ERC20Transfer) error {
-	// Обновляем статистику только для отправителя
-	if err := a.incrementERC20TransactionCountIfNotProcessed(transfer.From, transfer.TransactionHash); err != nil {
+	if err != nil {
 		return err
 	}
+	if !accounted {
+		logrus.Debugf("ERC20 трансфер уже учтен, пропускаем")
+		return nil
+	}
 
-	return nil
-}
+	// ИСПРАВЛЕНО: Обновляем статистику ERC20 транзакций только для новых уникальных транзакций
+	if err := a.updateERC20StatsForUniqueTransaction(erc20Transfer); err != nil {
+		logrus.Errorf("Ошибка обновления ERC20 статистики: %v", err)
+	}
 
-// Увеличиваем счетчик ERC20 транзакций только если еще не обрабатывали эту транзакцию для данного адреса
-func (a *Analyzer) incrementERC20TransactionCountIfNotProcessed(address, txHash string) error {
-	// Пропускаем нулевой адрес
-	if address == "0x0000000000000000000000000000000000000000" {
-		return nil
+	// Обновляем балансы токенов
+	if err := a.updateTokenBalances(erc20Transfer); err != nil {
+		logrus.Errorf("Ошибка обновления балансов токенов: %v", err)
 	}
 
-	// Проверяем, есть ли уже трансферы для этого адреса как отправителя
-	var count int64
-	result := repositories.DB.Model(&models.ERC20Transfer{}).
-		Where("transaction_hash = ? AND \"from\" = ?", txHash, address).
-		Count(&count)
+	// Учитываем перевод токена в графе взаимодействий
+	if err := a.graphRepo.RecordEdge(erc20Transfer.From, erc20Transfer.To, erc20Transfer.ContractAddress, value, erc20Transfer.Timestamp); err != nil {
+		logrus.Errorf("Ошибка обновления графа взаимодействий: %v", err)
+	}
 
-	if result.Error != nil {
-		return result.Error
+	// Mint и burn меняют эмиссию - пересчитываем историю
+	if erc20Transfer.Kind != models.TransferKindTransfer {
+		a.supplyTracker.Notify()
 	}
 
-	// Если это первый трансфер от этого адреса в этой транзакции, увеличиваем счетчик
-	if count > 0 {
-		if err := a.incrementERC20TransactionCount(address); err != nil {
-			return err
-		}
-		logrus.Debugf("Увеличен счетчик ERC20 для %s в транзакции %s", address, txHash)
+	// Регистрируем токен при первой встрече контракта
+	if _, err := a.tokenRegistry.GetToken(ctx, erc20Transfer.ContractAddress); err != nil && !errors.Is(err, errTokenFetchBackoff) {
+		logrus.Warnf("Не удалось получить метаданные токена %s: %v", erc20Transfer.ContractAddress, err)
 	}
 
+	logrus.Debugf("Сохранен ERC20 трансфер: %s от %s к %s значение %s",
+		erc20Transfer.ContractAddress, erc20Transfer.From, erc20Transfer.To,
+		a.tokenRegistry.FormatAmount(erc20Transfer.ContractAddress, value))
+
 	return nil
 }
 
-func (a *Analyzer) incrementERC20TransactionCount(address string) error {
-	// Получаем или создаем статистику аккаунта
-	stats, err := a.accountAnalyzer.accountRepo.GetOrCreateAccountStats(address)
-	if err != nil {
-		return err
+// updateERC20StatsForUniqueTransaction учитывает только что сохраненный трансфер в статистике отправителя и получателя.
+// Вызывается только для новых трансферов, поэтому повторная обработка не увеличивает счетчики
+func (a *Analyzer) updateERC20StatsForUniqueTransaction(transfer *models.ERC20Transfer) error {
+	// Пропускаем нулевой адрес (mint у отправителя, burn у получателя)
+	if transfer.From != models.ZeroAddress {
+		if err := a.accountAnalyzer.accountRepo.RecordERC20Transaction(transfer.From, true, transfer.Timestamp); err != nil {
+			return err
+		}
 	}
 
-	// Увеличиваем счетчик ERC20 транзакций
-	stats.ERC20Transactions++
-
-	// Сохраняем обновленную статистику
-	if err := a.accountAnalyzer.accountRepo.UpdateAccountStats(stats); err != nil {
-		return err
+	if transfer.To != models.ZeroAddress {
+		if err := a.accountAnalyzer.accountRepo.RecordERC20Transaction(transfer.To, false, transfer.Timestamp); err != nil {
+			return err
+		}
 	}
 
+	logrus.Debugf("Обновлены счетчики ERC20 для %s и %s в транзакции %s", transfer.From, transfer.To, transfer.TransactionHash)
 	return nil
 }
 
 func (a *Analyzer) updateTokenBalances(transfer *models.ERC20Transfer) error {
-	// Парсим значение
-	value, ok := big.NewInt(0).SetString(transfer.Value, 10)
-	if !ok {
-		return nil // Пропускаем если не удалось распарсить значение
-	}
-
-	// Пропускаем mint/burn операции (от/к нулевому адресу)
-	zeroAddress := "0x0000000000000000000000000000000000000000"
+	value := transfer.Value.Int()
 
 	// Обновляем баланс отправителя (если не mint)
-	if transfer.From != zeroAddress {
+	if transfer.Kind != models.TransferKindMint {
 		if err := a.accountAnalyzer.GetTokenTracker().UpdateTokenBalance(transfer.From, transfer.ContractAddress, value, false); err != nil {
 			logrus.Errorf("Ошибка обновления баланса отправителя %s: %v", transfer.From, err)
 		}
 	}
 
 	// Обновляем баланс получателя (если не burn)
-	if transfer.To != zeroAddress {
+	if transfer.Kind != models.TransferKindBurn {
 		if err := a.accountAnalyzer.GetTokenTracker().UpdateTokenBalance(transfer.To, transfer.ContractAddress, value, true); err != nil {
 			logrus.Errorf("Ошибка обновления баланса получателя %s: %v", transfer.To, err)
 		}
@@ -562,3 +795,16 @@ func (a *Analyzer) AnalyzeContract(ctx context.Context, contractAddress common.A
 	logrus.Infof("Анализ контракта %s завершен", contractAddress.Hex())
 	return nil
 }
+
+// hexAddresses преобразует адреса из конфигурации; пустой список - без фильтра
+func hexAddresses(addresses []string) []common.Address {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	result := make([]common.Address, len(addresses))
+	for i, address := range addresses {
+		result[i] = common.HexToAddress(address)
+	}
+	return result
+}
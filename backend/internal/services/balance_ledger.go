@@ -0,0 +1,474 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/internal/utils"
+	"backend/pkg/contracts"
+	"backend/pkg/ethereum"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+const ledgerVerifyInterval = 10 * time.Minute
+
+// BalanceLedger ведет леджер изменений балансов ETH и токенов по блокам,
+// отвечает на запросы баланса на блок или момент времени
+// и периодически сверяет балансы ETH с BalanceAt на последнем проиндексированном блоке
+type BalanceLedger struct {
+	ethClient     *ethereum.Client
+	historyRepo   *repositories.BalanceHistoryRepository
+	reconcileRepo *repositories.ReconciliationRepository
+	notifier      *Notifier
+	known         sync.Map    // ключ address|token - леджер уже начат
+	traceInternal atomic.Bool // нода поддерживает debug_traceTransaction
+	lastVerified  uint64      // блок последней сверки; меняется только в Verify
+	verifyMu      sync.Mutex
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+func NewBalanceLedger(ethClient *ethereum.Client, historyRepo *repositories.BalanceHistoryRepository, reconcileRepo *repositories.ReconciliationRepository, notifier *Notifier, traceInternal bool) *BalanceLedger {
+	ledger := &BalanceLedger{
+		ethClient:     ethClient,
+		historyRepo:   historyRepo,
+		reconcileRepo: reconcileRepo,
+		notifier:      notifier,
+	}
+	ledger.traceInternal.Store(traceInternal)
+	return ledger
+}
+
+func (l *BalanceLedger) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	l.cancel = cancel
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		ticker := time.NewTicker(ledgerVerifyInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := l.Verify(ctx); err != nil {
+					logrus.Errorf("Ошибка сверки леджера балансов ETH: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (l *BalanceLedger) Stop() {
+	if l.cancel != nil {
+		l.cancel()
+		l.wg.Wait()
+	}
+}
+
+// InternalTransfers возвращает внутренние переводы ETH транзакции.
+// Если нода не поддерживает трассировку, она отключается до перезапуска
+func (l *BalanceLedger) InternalTransfers(ctx context.Context, txHash common.Hash) ([]ethereum.InternalTransfer, error) {
+	if !l.traceInternal.Load() {
+		return nil, nil
+	}
+
+	transfers, err := l.ethClient.TraceInternalTransfers(ctx, txHash)
+	if ethereum.IsMethodNotSupported(err) {
+		if l.traceInternal.CompareAndSwap(true, false) {
+			logrus.Warnf("Нода не поддерживает debug_traceTransaction, внутренние переводы ETH не учитываются: %v", err)
+		}
+		return nil, nil
+	}
+	return transfers, err
+}
+
+// RecordTransaction записывает изменения баланса ETH участников транзакции:
+// value, комиссию отправителя и внутренние переводы. Изменения одного адреса суммируются в одну запись
+func (l *BalanceLedger) RecordTransaction(ctx context.Context, transaction *models.Transaction, txIndex uint, internal []ethereum.InternalTransfer) error {
+	var changes []models.BalanceChange
+	for address, delta := range transactionDeltas(transaction, internal) {
+		changes = append(changes, l.withOpening(ctx, models.BalanceChange{
+			Address:         address,
+			TokenAddress:    models.NativeAsset,
+			BlockNumber:     transaction.BlockNumber,
+			TxIndex:         txIndex,
+			TransactionHash: transaction.Hash,
+			LogIndex:        models.LogIndexNative,
+			Delta:           models.NewBigInt(delta),
+			Timestamp:       transaction.Timestamp,
+		})...)
+	}
+
+	return l.record(changes)
+}
+
+// transactionDeltas считает изменения балансов ETH от транзакции; нулевые изменения не возвращаются.
+// Отправитель платит газ всегда, а value и внутренние переводы применяются только при успешном выполнении
+func transactionDeltas(transaction *models.Transaction, internal []ethereum.InternalTransfer) map[string]*big.Int {
+	deltas := map[string]*big.Int{transaction.From: new(big.Int).Neg(transaction.GetTotalGasCost())}
+	addDelta := func(address string, delta *big.Int) {
+		if _, exists := deltas[address]; !exists {
+			deltas[address] = big.NewInt(0)
+		}
+		deltas[address].Add(deltas[address], delta)
+	}
+
+	if transaction.IsSuccessful() {
+		value := transaction.Value.Int()
+		addDelta(transaction.From, new(big.Int).Neg(value))
+		// value транзакции деплоя получает созданный контракт
+		if transaction.To != "" {
+			addDelta(transaction.To, value)
+		} else if transaction.ContractAddress != "" {
+			addDelta(transaction.ContractAddress, value)
+		}
+
+		for _, transfer := range internal {
+			addDelta(transfer.From.Hex(), new(big.Int).Neg(transfer.Value))
+			addDelta(transfer.To.Hex(), transfer.Value)
+		}
+	}
+
+	for address, delta := range deltas {
+		if delta.Sign() == 0 {
+			delete(deltas, address)
+		}
+	}
+	return deltas
+}
+
+// RecordBlock записывает изменения балансов ETH уровня блока: чаевые получателю комиссий и выводы из beacon chain.
+// Награда за блок до The Merge не учитывается - ее исправит сверка
+func (l *BalanceLedger) RecordBlock(ctx context.Context, block *types.Block, priorityFees *big.Int) error {
+	base := models.BalanceChange{
+		TokenAddress:    models.NativeAsset,
+		BlockNumber:     block.NumberU64(),
+		TxIndex:         models.TxIndexBlockEnd,
+		TransactionHash: block.Hash().Hex(),
+		Timestamp:       time.Unix(int64(block.Time()), 0),
+	}
+
+	var changes []models.BalanceChange
+	if priorityFees != nil && priorityFees.Sign() > 0 {
+		change := base
+		change.Address = block.Coinbase().Hex()
+		change.LogIndex = models.LogIndexBlockFees
+		change.Delta = models.NewBigInt(priorityFees)
+		changes = append(changes, l.withOpening(ctx, change)...)
+	}
+
+	// Сумма выводов указана в gwei
+	withdrawals := make(map[string]*big.Int)
+	for _, withdrawal := range block.Withdrawals() {
+		address := withdrawal.Address.Hex()
+		if _, exists := withdrawals[address]; !exists {
+			withdrawals[address] = big.NewInt(0)
+		}
+		amount := new(big.Int).Mul(new(big.Int).SetUint64(withdrawal.Amount), big.NewInt(1e9))
+		withdrawals[address].Add(withdrawals[address], amount)
+	}
+	for address, amount := range withdrawals {
+		if amount.Sign() == 0 {
+			continue
+		}
+		change := base
+		change.Address = address
+		change.LogIndex = models.LogIndexWithdrawal
+		change.Delta = models.NewBigInt(amount)
+		changes = append(changes, l.withOpening(ctx, change)...)
+	}
+
+	return l.record(changes)
+}
+
+// PriorityFee возвращает чаевые получателю комиссий блока за транзакцию: gasUsed * (effectiveGasPrice - baseFee)
+func PriorityFee(receipt *types.Receipt, baseFee *big.Int) *big.Int {
+	if receipt.EffectiveGasPrice == nil {
+		return big.NewInt(0)
+	}
+
+	tip := new(big.Int).Set(receipt.EffectiveGasPrice)
+	if baseFee != nil {
+		tip.Sub(tip, baseFee)
+	}
+	if tip.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+	return tip.Mul(tip, new(big.Int).SetUint64(receipt.GasUsed))
+}
+
+// CurrentBalance возвращает баланс ETH по леджеру; если записей по адресу нет,
+// читает баланс на последнем проиндексированном блоке
+func (l *BalanceLedger) CurrentBalance(ctx context.Context, address string) (*big.Int, error) {
+	balance, found, err := l.historyRepo.GetLatestBalance(address, models.NativeAsset)
+	if err != nil || found {
+		return balance, err
+	}
+
+	block, err := l.reconcileRepo.GetLastProcessedBlock()
+	if err != nil {
+		return nil, err
+	}
+	if block == 0 {
+		return big.NewInt(0), nil
+	}
+	return l.ethClient.GetBalanceAtBlock(ctx, common.HexToAddress(address), new(big.Int).SetUint64(block))
+}
+
+// Verify сверяет балансы ETH адресов, изменившихся с прошлой сверки, с BalanceAt
+// на последнем проиндексированном блоке и записывает поправки в леджер
+func (l *BalanceLedger) Verify(ctx context.Context) ([]models.BalanceChange, error) {
+	l.verifyMu.Lock()
+	defer l.verifyMu.Unlock()
+
+	block, err := l.reconcileRepo.GetLastProcessedBlock()
+	if err != nil {
+		return nil, err
+	}
+	if block == 0 || block <= l.lastVerified {
+		return nil, nil
+	}
+
+	addresses, err := l.historyRepo.GetAddressesChangedSince(models.NativeAsset, l.lastVerified, block)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNumber := new(big.Int).SetUint64(block)
+	header, err := l.ethClient.GetClient().HeaderByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения заголовка блока #%d: %v", block, err)
+	}
+
+	holders := make([]common.Address, len(addresses))
+	for i, address := range addresses {
+		holders[i] = common.HexToAddress(address)
+	}
+
+	onChain, err := l.fetchEthBalances(ctx, holders, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var corrections []models.BalanceChange
+	for i, holder := range holders {
+		actual, ok := onChain[holder]
+		if !ok {
+			logrus.Warnf("Не удалось получить баланс ETH %s на блоке #%d", holder.Hex(), block)
+			continue
+		}
+
+		stored, err := l.historyRepo.GetBalanceAtBlock(addresses[i], models.NativeAsset, block)
+		if err != nil {
+			return nil, err
+		}
+		if stored.Cmp(actual) == 0 {
+			continue
+		}
+
+		corrections = append(corrections, models.BalanceChange{
+			Address:         addresses[i],
+			TokenAddress:    models.NativeAsset,
+			BlockNumber:     block,
+			TxIndex:         models.TxIndexBlockEnd,
+			TransactionHash: header.Hash().Hex(),
+			LogIndex:        models.LogIndexCorrection,
+			Delta:           models.NewBigInt(new(big.Int).Sub(actual, stored)),
+			Timestamp:       time.Unix(int64(header.Time), 0),
+		})
+	}
+
+	if err := l.record(corrections); err != nil {
+		return nil, fmt.Errorf("ошибка записи поправок леджера: %v", err)
+	}
+	l.lastVerified = block
+
+	logrus.Infof("Сверка леджера ETH на блоке #%d: проверено %d адресов, исправлено %d",
+		block, len(holders), len(corrections))
+
+	l.report(corrections, block)
+	return corrections, nil
+}
+
+// fetchEthBalances читает балансы ETH через Multicall3, а если он не задеплоен - по одному вызову на адрес
+func (l *BalanceLedger) fetchEthBalances(ctx context.Context, holders []common.Address, blockNumber *big.Int) (map[common.Address]*big.Int, error) {
+	multicall, err := l.ethClient.GetMulticall()
+	if err == nil {
+		deployed, err := multicall.IsDeployed(ctx)
+		if err == nil && deployed {
+			return multicall.EthBalancesOf(ctx, holders, blockNumber)
+		}
+	}
+
+	logrus.Debug("Multicall3 недоступен, читаем балансы ETH по одному")
+
+	balances := make(map[common.Address]*big.Int, len(holders))
+	for _, holder := range holders {
+		balance, err := l.ethClient.GetBalanceAtBlock(ctx, holder, blockNumber)
+		if err != nil {
+			logrus.Debugf("Ошибка чтения баланса ETH для %s: %v", holder.Hex(), err)
+			continue
+		}
+		balances[holder] = balance
+	}
+
+	return balances, nil
+}
+
+// report оповещает о поправках леджера
+func (l *BalanceLedger) report(corrections []models.BalanceChange, block uint64) {
+	if len(corrections) == 0 {
+		return
+	}
+
+	sort.Slice(corrections, func(i, j int) bool { return corrections[i].Address < corrections[j].Address })
+
+	var lines []string
+	for _, c := range corrections {
+		logrus.Warnf("Поправка леджера ETH %s на блоке #%d: %s", c.Address, block, utils.FormatETH(c.Delta.Int()))
+
+		if len(lines) < maxAlertLines {
+			lines = append(lines, fmt.Sprintf("<code>%s</code>: %s", c.Address, utils.FormatETH(c.Delta.Int())))
+		}
+	}
+
+	if l.notifier == nil {
+		return
+	}
+
+	if len(corrections) > len(lines) {
+		lines = append(lines, fmt.Sprintf("... и еще %d", len(corrections)-len(lines)))
+	}
+
+	message := fmt.Sprintf("🔧 <b>Исправлен леджер балансов ETH</b> (блок #%d, адресов: %d)\n\n%s",
+		block, len(corrections), strings.Join(lines, "\n"))
+
+	if err := l.notifier.SendAlert(message); err != nil {
+		logrus.Errorf("Ошибка отправки отчета о сверке леджера: %v", err)
+	}
+}
+
+// RecordTransfer записывает изменения баланса токена участников трансфера (mint и burn меняют одну сторону)
+func (l *BalanceLedger) RecordTransfer(ctx context.Context, transfer *models.ERC20Transfer, txIndex, logIndex uint, timestamp time.Time) error {
+	value := transfer.Value.Int()
+	if value.Sign() == 0 || transfer.From == transfer.To {
+		return nil
+	}
+
+	base := models.BalanceChange{
+		TokenAddress:    transfer.ContractAddress,
+		BlockNumber:     transfer.BlockNumber,
+		TxIndex:         txIndex,
+		TransactionHash: transfer.TransactionHash,
+		LogIndex:        int(logIndex),
+		Timestamp:       timestamp,
+	}
+
+	var changes []models.BalanceChange
+	if transfer.Kind != models.TransferKindMint {
+		change := base
+		change.Address = transfer.From
+		change.Delta = models.NewBigInt(new(big.Int).Neg(value))
+		changes = append(changes, l.withOpening(ctx, change)...)
+	}
+	if transfer.Kind != models.TransferKindBurn {
+		change := base
+		change.Address = transfer.To
+		change.Delta = models.NewBigInt(value)
+		changes = append(changes, l.withOpening(ctx, change)...)
+	}
+
+	return l.record(changes)
+}
+
+// BalanceAtBlock возвращает баланс адреса на конец блока; для ETH используйте models.NativeAsset
+func (l *BalanceLedger) BalanceAtBlock(address, tokenAddress string, block uint64) (*big.Int, error) {
+	return l.historyRepo.GetBalanceAtBlock(address, tokenAddress, block)
+}
+
+// BalanceAtTime возвращает баланс адреса на момент времени
+func (l *BalanceLedger) BalanceAtTime(address, tokenAddress string, at time.Time) (*big.Int, error) {
+	return l.historyRepo.GetBalanceAtTime(address, tokenAddress, at)
+}
+
+// BalanceSeries возвращает изменения баланса адреса за период
+func (l *BalanceLedger) BalanceSeries(address, tokenAddress string, from, to time.Time) ([]models.BalanceChange, error) {
+	return l.historyRepo.GetBalanceSeries(address, tokenAddress, from, to)
+}
+
+func (l *BalanceLedger) record(changes []models.BalanceChange) error {
+	if err := l.historyRepo.RecordChanges(changes); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		l.known.Store(change.Address+"|"+change.TokenAddress, struct{}{})
+	}
+	return nil
+}
+
+// withOpening добавляет перед первым изменением леджера начальный баланс на предыдущем блоке,
+// чтобы история была абсолютной, даже если индексация началась не с генезиса
+func (l *BalanceLedger) withOpening(ctx context.Context, change models.BalanceChange) []models.BalanceChange {
+	key := change.Address + "|" + change.TokenAddress
+	if _, ok := l.known.Load(key); ok {
+		return []models.BalanceChange{change}
+	}
+
+	exists, err := l.historyRepo.HasHistory(change.Address, change.TokenAddress)
+	if err != nil || exists || change.BlockNumber == 0 {
+		if exists {
+			l.known.Store(key, struct{}{})
+		}
+		return []models.BalanceChange{change}
+	}
+
+	previous := new(big.Int).SetUint64(change.BlockNumber - 1)
+	opening, err := l.fetchBalance(ctx, change.Address, change.TokenAddress, previous)
+	if err != nil {
+		logrus.Debugf("Не удалось получить начальный баланс %s токен %s на блоке #%d: %v",
+			change.Address, change.TokenAddress, previous.Uint64(), err)
+		return []models.BalanceChange{change}
+	}
+	if opening.Sign() == 0 {
+		return []models.BalanceChange{change}
+	}
+
+	return []models.BalanceChange{{
+		Address:      change.Address,
+		TokenAddress: change.TokenAddress,
+		BlockNumber:  previous.Uint64(),
+		LogIndex:     models.LogIndexOpening,
+		Delta:        models.NewBigInt(opening),
+		Timestamp:    change.Timestamp,
+	}, change}
+}
+
+func (l *BalanceLedger) fetchBalance(ctx context.Context, address, tokenAddress string, block *big.Int) (*big.Int, error) {
+	account := common.HexToAddress(address)
+	if tokenAddress == models.NativeAsset {
+		return l.ethClient.GetBalanceAtBlock(ctx, account, block)
+	}
+
+	token, err := contracts.NewERC20Contract(common.HexToAddress(tokenAddress), l.ethClient.GetClient())
+	if err != nil {
+		return nil, err
+	}
+	return token.BalanceOfAtBlock(ctx, account, block)
+}
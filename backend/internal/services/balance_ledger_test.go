@@ -0,0 +1,104 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/pkg/ethereum"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ledgerSender   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	ledgerReceiver = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	ledgerContract = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+func TestTransactionDeltas(t *testing.T) {
+	// Газ: 21000 * 10 = 210000 wei
+	base := models.Transaction{
+		From:     ledgerSender.Hex(),
+		To:       ledgerReceiver.Hex(),
+		Value:    models.NewBigInt(big.NewInt(1000000)),
+		GasUsed:  21000,
+		GasPrice: models.NewBigInt(big.NewInt(10)),
+		Status:   1,
+	}
+
+	t.Run("успешный перевод", func(t *testing.T) {
+		tx := base
+		assertDeltas(t, transactionDeltas(&tx, nil), map[common.Address]int64{
+			ledgerSender:   -1210000,
+			ledgerReceiver: 1000000,
+		})
+	})
+
+	t.Run("revert списывает только газ", func(t *testing.T) {
+		tx := base
+		tx.Status = 0
+		internal := []ethereum.InternalTransfer{{From: ledgerReceiver, To: ledgerContract, Value: big.NewInt(5)}}
+		assertDeltas(t, transactionDeltas(&tx, internal), map[common.Address]int64{
+			ledgerSender: -210000,
+		})
+	})
+
+	t.Run("value деплоя получает созданный контракт", func(t *testing.T) {
+		tx := base
+		tx.To = ""
+		tx.ContractAddress = ledgerContract.Hex()
+		assertDeltas(t, transactionDeltas(&tx, nil), map[common.Address]int64{
+			ledgerSender:   -1210000,
+			ledgerContract: 1000000,
+		})
+	})
+
+	t.Run("revert деплоя не зачисляет value", func(t *testing.T) {
+		tx := base
+		tx.To = ""
+		tx.ContractAddress = ledgerContract.Hex()
+		tx.Status = 0
+		assertDeltas(t, transactionDeltas(&tx, nil), map[common.Address]int64{
+			ledgerSender: -210000,
+		})
+	})
+
+	t.Run("внутренние переводы и взаимное погашение", func(t *testing.T) {
+		tx := base
+		tx.To = ledgerContract.Hex()
+		// Контракт пересылает полученное value получателю и возвращает часть отправителю
+		internal := []ethereum.InternalTransfer{
+			{From: ledgerContract, To: ledgerReceiver, Value: big.NewInt(600000)},
+			{From: ledgerContract, To: ledgerSender, Value: big.NewInt(400000)},
+		}
+		assertDeltas(t, transactionDeltas(&tx, internal), map[common.Address]int64{
+			ledgerSender:   -810000,
+			ledgerReceiver: 600000,
+		})
+	})
+
+	t.Run("перевод самому себе без газа", func(t *testing.T) {
+		tx := base
+		tx.To = ledgerSender.Hex()
+		tx.GasPrice = models.NewBigInt(big.NewInt(0))
+		assertDeltas(t, transactionDeltas(&tx, nil), map[common.Address]int64{})
+	})
+}
+
+func assertDeltas(t *testing.T, got map[string]*big.Int, want map[common.Address]int64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("получено %d изменений %v, ожидалось %d", len(got), got, len(want))
+	}
+	for address, delta := range want {
+		value, exists := got[address.Hex()]
+		if !exists {
+			t.Errorf("нет изменения баланса %s", address.Hex())
+			continue
+		}
+		if value.Cmp(big.NewInt(delta)) != 0 {
+			t.Errorf("изменение баланса %s = %s, ожидалось %d", address.Hex(), value, delta)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/contracts"
+	"backend/pkg/ethereum"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+const balanceReconcileInterval = 10 * time.Minute
+
+// BalanceReconciler периодически сверяет сохраненные балансы токена с on-chain balanceOf
+// на последнем проиндексированном блоке и исправляет дрейф
+type BalanceReconciler struct {
+	ethClient     *ethereum.Client
+	reconcileRepo *repositories.ReconciliationRepository
+	notifier      *Notifier
+	tokenRegistry *TokenRegistry
+	tokenAddress  common.Address
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+func NewBalanceReconciler(ethClient *ethereum.Client, reconcileRepo *repositories.ReconciliationRepository, notifier *Notifier, tokenRegistry *TokenRegistry, tokenAddress common.Address) *BalanceReconciler {
+	return &BalanceReconciler{
+		ethClient:     ethClient,
+		reconcileRepo: reconcileRepo,
+		notifier:      notifier,
+		tokenRegistry: tokenRegistry,
+		tokenAddress:  tokenAddress,
+	}
+}
+
+func (r *BalanceReconciler) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(balanceReconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.Reconcile(ctx); err != nil {
+					logrus.Errorf("Ошибка сверки балансов токена: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *BalanceReconciler) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+}
+
+// Reconcile сверяет балансы всех держателей токена и возвращает внесенные исправления
+func (r *BalanceReconciler) Reconcile(ctx context.Context) ([]models.BalanceCorrection, error) {
+	block, err := r.reconcileRepo.GetLastProcessedBlock()
+	if err != nil {
+		return nil, err
+	}
+	if block == 0 {
+		return nil, nil
+	}
+	blockNumber := new(big.Int).SetUint64(block)
+
+	stored, err := r.loadStoredBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := r.reconcileRepo.GetTokenParticipants(r.tokenAddress.Hex())
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		holder := common.HexToAddress(participant)
+		if _, exists := stored[holder]; !exists {
+			stored[holder] = nil
+		}
+	}
+
+	// Нулевой адрес участвует только в mint/burn и не является держателем
+	delete(stored, common.Address{})
+
+	holders := make([]common.Address, 0, len(stored))
+	for holder := range stored {
+		holders = append(holders, holder)
+	}
+
+	onChain, err := r.fetchBalances(ctx, holders, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	runID := fmt.Sprintf("%x", time.Now().UnixNano())
+	correctedAt := time.Now()
+
+	var corrections []models.BalanceCorrection
+	for _, holder := range holders {
+		actual, ok := onChain[holder]
+		if !ok {
+			logrus.Warnf("Не удалось получить balanceOf для %s на блоке #%d", holder.Hex(), block)
+			continue
+		}
+
+		address := holder.Hex()
+		storedValue := big.NewInt(0)
+		if balance := stored[holder]; balance != nil {
+			address = balance.Address
+			storedValue = balance.Balance.Int()
+		}
+
+		if storedValue.Cmp(actual) == 0 {
+			continue
+		}
+
+		corrections = append(corrections, models.BalanceCorrection{
+			RunID:          runID,
+			TokenAddress:   r.tokenAddress.Hex(),
+			Address:        address,
+			StoredBalance:  models.NewBigInt(storedValue),
+			OnChainBalance: models.NewBigInt(actual),
+			Difference:     models.NewBigInt(new(big.Int).Sub(actual, storedValue)),
+			BlockNumber:    block,
+			CorrectedAt:    correctedAt,
+		})
+	}
+
+	if err := r.reconcileRepo.ApplyBalanceCorrections(corrections); err != nil {
+		return nil, fmt.Errorf("ошибка применения исправлений балансов: %v", err)
+	}
+
+	logrus.Infof("Сверка балансов на блоке #%d: проверено %d держателей, исправлено %d",
+		block, len(holders), len(corrections))
+
+	r.report(corrections, block)
+	return corrections, nil
+}
+
+// loadStoredBalances возвращает сохраненные балансы токена по адресу держателя
+func (r *BalanceReconciler) loadStoredBalances() (map[common.Address]*models.TokenBalance, error) {
+	balances, err := r.reconcileRepo.GetStoredTokenBalances(r.tokenAddress.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[common.Address]*models.TokenBalance, len(balances))
+	for i := range balances {
+		stored[common.HexToAddress(balances[i].Address)] = &balances[i]
+	}
+
+	return stored, nil
+}
+
+// fetchBalances читает balanceOf через Multicall3, а если он не задеплоен - по одному вызову на держателя
+func (r *BalanceReconciler) fetchBalances(ctx context.Context, holders []common.Address, blockNumber *big.Int) (map[common.Address]*big.Int, error) {
+	multicall, err := r.ethClient.GetMulticall()
+	if err == nil {
+		deployed, err := multicall.IsDeployed(ctx)
+		if err == nil && deployed {
+			return multicall.BalancesOf(ctx, r.tokenAddress, holders, blockNumber)
+		}
+	}
+
+	logrus.Debug("Multicall3 недоступен, читаем балансы по одному")
+
+	token, err := contracts.NewERC20Contract(r.tokenAddress, r.ethClient.GetClient())
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[common.Address]*big.Int, len(holders))
+	for _, holder := range holders {
+		balance, err := token.BalanceOfAtBlock(ctx, holder, blockNumber)
+		if err != nil {
+			logrus.Debugf("Ошибка чтения balanceOf для %s: %v", holder.Hex(), err)
+			continue
+		}
+		balances[holder] = balance
+	}
+
+	return balances, nil
+}
+
+// report оповещает о затронутых аккаунтах
+func (r *BalanceReconciler) report(corrections []models.BalanceCorrection, block uint64) {
+	if len(corrections) == 0 {
+		return
+	}
+
+	var lines []string
+	for _, c := range corrections {
+		logrus.Warnf("Исправлен баланс %s: было %s, на блоке #%d %s",
+			c.Address, r.formatAmount(c.StoredBalance), block, r.formatAmount(c.OnChainBalance))
+
+		if len(lines) < maxAlertLines {
+			lines = append(lines, fmt.Sprintf("<code>%s</code>: %s → %s",
+				c.Address, r.formatAmount(c.StoredBalance), r.formatAmount(c.OnChainBalance)))
+		}
+	}
+
+	if r.notifier == nil {
+		return
+	}
+
+	if len(corrections) > len(lines) {
+		lines = append(lines, fmt.Sprintf("... и еще %d", len(corrections)-len(lines)))
+	}
+
+	message := fmt.Sprintf("🔧 <b>Исправлены балансы токена</b> (блок #%d, аккаунтов: %d)\n\n%s",
+		block, len(corrections), strings.Join(lines, "\n"))
+
+	if err := r.notifier.SendAlert(message); err != nil {
+		logrus.Errorf("Ошибка отправки отчета об исправлении балансов: %v", err)
+	}
+}
+
+func (r *BalanceReconciler) formatAmount(value models.BigInt) string {
+	if r.tokenRegistry == nil {
+		return value.String()
+	}
+	return r.tokenRegistry.FormatAmount(r.tokenAddress.Hex(), value.Int())
+}
@@ -5,6 +5,7 @@ import (
 	"backend/internal/repositories"
 	"backend/pkg/contracts"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -14,30 +15,134 @@ import (
 
 	"backend/pkg/ethereum"
 
-	"encoding/hex"
+	"encoding/json"
+	"os"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
 type ContractAnalyzer struct {
-	ethClient    *ethereum.Client
-	accountRepo  *repositories.AccountRepository
-	contracts    map[common.Address]*contracts.ERC20Contract
-	tokenAddress common.Address // Адрес нашего токена
+	ethClient     *ethereum.Client
+	accountRepo   *repositories.AccountRepository
+	tokenRegistry *TokenRegistry
+	signatureRepo *repositories.SignatureRepository
+	graphRepo     *repositories.GraphRepository
+	decoder       *contracts.MethodDecoder
+	contracts     map[common.Address]*contracts.ERC20Contract
+	tokenAddress  common.Address // Адрес нашего токена
 }
 
-func NewContractAnalyzer(ethClient *ethereum.Client, accountRepo *repositories.AccountRepository, tokenAddress common.Address) *ContractAnalyzer {
+func NewContractAnalyzer(ethClient *ethereum.Client, accountRepo *repositories.AccountRepository, signatureRepo *repositories.SignatureRepository, graphRepo *repositories.GraphRepository, tokenRegistry *TokenRegistry, tokenAddress common.Address) *ContractAnalyzer {
 	return &ContractAnalyzer{
-		ethClient:    ethClient,
-		accountRepo:  accountRepo,
-		contracts:    make(map[common.Address]*contracts.ERC20Contract),
-		tokenAddress: tokenAddress,
+		ethClient:     ethClient,
+		accountRepo:   accountRepo,
+		tokenRegistry: tokenRegistry,
+		signatureRepo: signatureRepo,
+		graphRepo:     graphRepo,
+		decoder:       newMethodDecoder(signatureRepo),
+		contracts:     make(map[common.Address]*contracts.ERC20Contract),
+		tokenAddress:  tokenAddress,
 	}
 }
 
+// newMethodDecoder собирает декодер из встроенных ABI и локальной базы сигнатур
+func newMethodDecoder(signatureRepo *repositories.SignatureRepository) *contracts.MethodDecoder {
+	decoder := contracts.NewMethodDecoder()
+
+	loaders := []func() (abi.ABI, error){
+		contracts.LoadAnalyzerTokenABI,
+		contracts.LoadERC20ABI,
+		contracts.LoadMulticall3ABI,
+	}
+	for _, load := range loaders {
+		parsed, err := load()
+		if err != nil {
+			logrus.Errorf("Ошибка загрузки ABI для декодера: %v", err)
+			continue
+		}
+		decoder.AddABI(parsed)
+	}
+
+	signatures, err := signatureRepo.GetAllSignatures()
+	if err != nil {
+		logrus.Errorf("Ошибка загрузки базы сигнатур: %v", err)
+		return decoder
+	}
+
+	for _, signature := range signatures {
+		if err := decoder.AddSignature(signature.Signature); err != nil {
+			logrus.Debugf("Пропускаем сигнатуру %s: %v", signature.Signature, err)
+		}
+	}
+
+	logrus.Debugf("Загружено %d сигнатур методов из локальной базы", len(signatures))
+	return decoder
+}
+
+// ImportSignatureFile импортирует сигнатуры методов из файла в локальную базу и декодер
+func (ca *ContractAnalyzer) ImportSignatureFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения файла сигнатур %s: %v", path, err)
+	}
+
+	parsed, err := contracts.ParseSignatureFile(data)
+	if err != nil {
+		return err
+	}
+
+	signatures := make([]models.MethodSignature, 0, len(parsed))
+	for _, signature := range parsed {
+		selector, err := contracts.SignatureSelector(signature)
+		if err != nil {
+			logrus.Debugf("Пропускаем сигнатуру %s: %v", signature, err)
+			continue
+		}
+		if err := ca.decoder.AddSignature(signature); err != nil {
+			continue
+		}
+		signatures = append(signatures, models.MethodSignature{
+			Selector:  selector,
+			Signature: strings.ReplaceAll(signature, " ", ""),
+		})
+	}
+
+	imported, err := ca.signatureRepo.ImportSignatures(signatures)
+	if err != nil {
+		return fmt.Errorf("ошибка импорта сигнатур: %v", err)
+	}
+
+	logrus.Infof("Импортировано %d новых сигнатур методов из %s", imported, path)
+	return nil
+}
+
+// DecodeMethod определяет метод и аргументы вызова; для неизвестных селекторов возвращает "unknown"
+func (ca *ContractAnalyzer) DecodeMethod(input []byte) (method, methodID, signature, args string) {
+	method, args = "unknown", "null"
+	if len(input) >= 4 {
+		methodID = fmt.Sprintf("0x%x", input[:4])
+	}
+
+	decoded, err := ca.decoder.Decode(input)
+	if err != nil {
+		if len(input) >= 4 {
+			logrus.Debugf("Не удалось декодировать вызов %s: %v", methodID, err)
+		}
+		return method, methodID, "", args
+	}
+
+	encoded, err := json.Marshal(decoded.Args)
+	if err != nil {
+		logrus.Errorf("Ошибка сериализации аргументов %s: %v", decoded.Signature, err)
+		encoded = []byte("null")
+	}
+
+	return decoded.Name, decoded.Selector, decoded.Signature, string(encoded)
+}
+
 // GetOrCreateContract получает или создает инстанс контракта по адресу
 func (ca *ContractAnalyzer) GetOrCreateContract(address common.Address) (*contracts.ERC20Contract, error) {
 	if contract, exists := ca.contracts[address]; exists {
@@ -60,7 +165,7 @@ func (ca *ContractAnalyzer) AnalyzeContractTransactions(ctx context.Context, fro
 		return err
 	}
 
-	// Создаем фильтр для событий Transfer
+	// Создаем фильтр для событий Transfer; TransactionRecorded нужен, чтобы понять, учел ли перевод контракт
 	filterQuery := eth.FilterQuery{
 		FromBlock: big.NewInt(int64(fromBlock)),
 		ToBlock:   big.NewInt(int64(toBlock)),
@@ -68,6 +173,7 @@ func (ca *ContractAnalyzer) AnalyzeContractTransactions(ctx context.Context, fro
 		Topics: [][]common.Hash{{
 			// Transfer event signature
 			common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+			contracts.TransactionRecordedTopic,
 		}},
 	}
 
@@ -80,8 +186,18 @@ func (ca *ContractAnalyzer) AnalyzeContractTransactions(ctx context.Context, fro
 	logrus.Infof("Найдено %d событий для контракта %s", len(logs), ca.tokenAddress.Hex())
 
 	// Обрабатываем каждый лог
-	for _, log := range logs {
-		if err := ca.processTransferLog(ctx, contract, &log); err != nil {
+	for i := range logs {
+		log := &logs[i]
+		if log.Topics[0] == contracts.TransactionRecordedTopic {
+			continue
+		}
+
+		var next *types.Log
+		if i+1 < len(logs) {
+			next = &logs[i+1]
+		}
+
+		if err := ca.processTransferLog(ctx, contract, log, next); err != nil {
 			logrus.Errorf("Ошибка обработки лога %s: %v", log.TxHash.Hex(), err)
 			continue
 		}
@@ -91,7 +207,7 @@ func (ca *ContractAnalyzer) AnalyzeContractTransactions(ctx context.Context, fro
 }
 
 // processTransferLog обрабатывает отдельное событие Transfer
-func (ca *ContractAnalyzer) processTransferLog(ctx context.Context, contract *contracts.ERC20Contract, log *types.Log) error {
+func (ca *ContractAnalyzer) processTransferLog(ctx context.Context, contract *contracts.ERC20Contract, log, next *types.Log) error {
 	// Проверяем, что это наш токен
 	if log.Address != ca.tokenAddress {
 		return nil // Пропускаем другие токены
@@ -119,117 +235,87 @@ func (ca *ContractAnalyzer) processTransferLog(ctx context.Context, contract *co
 	// Создаем запись о трансфере
 	transfer := &models.ERC20Transfer{
 		TransactionHash: log.TxHash.Hex(),
+		LogIndex:        log.Index,
 		ContractAddress: log.Address.Hex(),
 		From:            from.Hex(),
 		To:              to.Hex(),
-		Value:           value.String(),
+		Value:           models.NewBigInt(value),
+		Kind:            models.TransferKindOf(from.Hex(), to.Hex()),
 		BlockNumber:     log.BlockNumber,
-		CreatedAt:       time.Unix(int64(block.Time()), 0),
+		Timestamp:       time.Unix(int64(block.Time()), 0),
+		Recorded:        contracts.IsRecordedTransfer(log, next),
 	}
 
-	// Сохраняем в БД
-	if err := repositories.DB.Create(transfer).Error; err != nil {
-		// Если ошибка дублирования, просто игнорируем
-		if err.Error() == "duplicate key value violates unique constraint" {
-			logrus.Debugf("ERC20 трансфер уже существует, пропускаем")
-			return nil
-		}
+	// Сохраняем в БД; трансфер мог уже сохранить анализатор блоков или слушатель событий,
+	// поэтому побочные эффекты выполняем, только если учет трансфера достался нам
+	accounted, err := repositories.SaveTransferForAccounting(repositories.DB, transfer)
+	if err != nil {
 		return fmt.Errorf("ошибка сохранения трансфера: %v", err)
 	}
+	if !accounted {
+		logrus.Debugf("ERC20 трансфер уже учтен, пропускаем")
+		return nil
+	}
 
 	// Обновляем балансы токенов
 	if err := ca.updateTokenBalances(transfer); err != nil {
 		logrus.Errorf("Ошибка обновления балансов токенов: %v", err)
 	}
 
-	// Обновляем статистику только для отправителя
-	if err := ca.updateAccountStats(transfer.From, transfer, true); err != nil {
+	// Обновляем статистику отправителя и получателя
+	if err := ca.updateAccountStats(transfer.From, true, transfer); err != nil {
 		logrus.Errorf("Ошибка обновления статистики отправителя %s: %v", transfer.From, err)
 	}
+	if err := ca.updateAccountStats(transfer.To, false, transfer); err != nil {
+		logrus.Errorf("Ошибка обновления статистики получателя %s: %v", transfer.To, err)
+	}
+
+	// Учитываем перевод токена в графе взаимодействий
+	if err := ca.graphRepo.RecordEdge(transfer.From, transfer.To, transfer.ContractAddress, value, transfer.Timestamp); err != nil {
+		logrus.Errorf("Ошибка обновления графа взаимодействий: %v", err)
+	}
+
+	// Регистрируем токен при первой встрече контракта
+	if _, err := ca.tokenRegistry.GetToken(ctx, transfer.ContractAddress); err != nil && !errors.Is(err, errTokenFetchBackoff) {
+		logrus.Warnf("Не удалось получить метаданные токена %s: %v", transfer.ContractAddress, err)
+	}
 
 	logrus.Debugf("Сохранен трансфер: %s от %s к %s, значение %s",
-		transfer.TransactionHash, transfer.From, transfer.To, transfer.Value)
+		transfer.TransactionHash, transfer.From, transfer.To,
+		ca.tokenRegistry.FormatAmount(transfer.ContractAddress, value))
 
 	return nil
 }
 
-// updateTokenBalances обновляет балансы токенов для участников трансфера
+// updateTokenBalances атомарно обновляет балансы токенов для участников трансфера
 func (ca *ContractAnalyzer) updateTokenBalances(transfer *models.ERC20Transfer) error {
-	return repositories.DB.Transaction(func(tx *gorm.DB) error {
-		// Парсим значение
-		value, ok := big.NewInt(0).SetString(transfer.Value, 10)
-		if !ok {
-			return fmt.Errorf("ошибка парсинга значения трансфера: %s", transfer.Value)
-		}
-
-		// Пропускаем нулевой адрес
-		zeroAddress := "0x0000000000000000000000000000000000000000"
+	value := transfer.Value.Int()
 
-		// Обновляем баланс отправителя
-		if transfer.From != zeroAddress {
-			fromBalance, err := ca.accountRepo.GetTokenBalance(transfer.From, transfer.ContractAddress)
-			if err != nil {
-				return err
-			}
-			currentBalance := fromBalance.GetBalance()
-			newBalance := new(big.Int).Sub(currentBalance, value)
-			if newBalance.Sign() < 0 {
-				newBalance = big.NewInt(0)
-			}
-			fromBalance.SetBalance(newBalance)
-			if err := ca.accountRepo.UpdateTokenBalance(fromBalance); err != nil {
-				return err
-			}
+	// Обновляем баланс отправителя (если не mint)
+	if transfer.Kind != models.TransferKindMint {
+		if _, err := ca.accountRepo.AdjustTokenBalance(transfer.From, transfer.ContractAddress, new(big.Int).Neg(value)); err != nil {
+			return err
 		}
+	}
 
-		// Обновляем баланс получателя
-		if transfer.To != zeroAddress {
-			toBalance, err := ca.accountRepo.GetTokenBalance(transfer.To, transfer.ContractAddress)
-			if err != nil {
-				return err
-			}
-			currentBalance := toBalance.GetBalance()
-			newBalance := new(big.Int).Add(currentBalance, value)
-			toBalance.SetBalance(newBalance)
-			if err := ca.accountRepo.UpdateTokenBalance(toBalance); err != nil {
-				return err
-			}
+	// Обновляем баланс получателя (если не burn)
+	if transfer.Kind != models.TransferKindBurn {
+		if _, err := ca.accountRepo.AdjustTokenBalance(transfer.To, transfer.ContractAddress, value); err != nil {
+			return err
 		}
+	}
 
-		return nil
-	})
+	return nil
 }
 
-// updateAccountStats обновляет статистику аккаунта
-func (ca *ContractAnalyzer) updateAccountStats(address string, transfer *models.ERC20Transfer, isSender bool) error {
-	// Пропускаем нулевой адрес
-	if address == "0x0000000000000000000000000000000000000000" {
+// updateAccountStats атомарно учитывает исходящую или входящую ERC20 транзакцию в статистике аккаунта
+func (ca *ContractAnalyzer) updateAccountStats(address string, isSender bool, transfer *models.ERC20Transfer) error {
+	// Пропускаем нулевой адрес (mint/burn)
+	if address == models.ZeroAddress {
 		return nil
 	}
 
-	stats, err := ca.accountRepo.GetOrCreateAccountStats(address)
-	if err != nil {
-		return err
-	}
-
-	// Увеличиваем счетчик ERC20 транзакций
-	stats.ERC20Transactions++
-
-	// Обновляем время активности
-	now := transfer.CreatedAt
-	if stats.LastActivityTime == nil || now.After(*stats.LastActivityTime) {
-		stats.LastActivityTime = &now
-	}
-	if stats.FirstActivityTime == nil || now.Before(*stats.FirstActivityTime) {
-		stats.FirstActivityTime = &now
-	}
-
-	// Сохраняем обновленную статистику
-	if err := ca.accountRepo.UpdateAccountStats(stats); err != nil {
-		return err
-	}
-
-	return nil
+	return ca.accountRepo.RecordERC20Transaction(address, isSender, transfer.Timestamp)
 }
 
 // GetContractTransfers получает все трансферы контракта
@@ -255,7 +341,7 @@ func (ca *ContractAnalyzer) GetContractStats(contractAddress string) (map[string
 	var stats struct {
 		TotalTransfers  int64
 		UniqueAddresses int64
-		TotalVolume     string
+		TotalVolume     models.BigInt
 	}
 
 	// Получаем общее количество трансферов
@@ -295,15 +381,16 @@ func (ca *ContractAnalyzer) GetContractStats(contractAddress string) (map[string
 	// Получаем общий объем трансферов
 	if err := repositories.DB.Model(&models.ERC20Transfer{}).
 		Where("contract_address = ?", contractAddress).
-		Select("COALESCE(SUM(CAST(value AS NUMERIC)), 0)").
+		Select("COALESCE(SUM(value), 0)").
 		Row().Scan(&stats.TotalVolume); err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"total_transfers":  stats.TotalTransfers,
-		"unique_addresses": stats.UniqueAddresses,
-		"total_volume":     stats.TotalVolume,
+		"total_transfers":        stats.TotalTransfers,
+		"unique_addresses":       stats.UniqueAddresses,
+		"total_volume":           stats.TotalVolume.String(),
+		"total_volume_formatted": ca.tokenRegistry.FormatAmount(contractAddress, stats.TotalVolume.Int()),
 	}, nil
 }
 
@@ -363,20 +450,8 @@ func (ca *ContractAnalyzer) GetContractTransactions(ctx context.Context, contrac
 			continue
 		}
 
-		// Определяем метод из input данных
-		method := "unknown"
-		if len(tx.Data()) >= 4 {
-			methodID := hex.EncodeToString(tx.Data()[:4])
-			// Здесь можно добавить маппинг известных методов
-			switch methodID {
-			case "a9059cbb":
-				method = "transfer"
-			case "23b872dd":
-				method = "transferFrom"
-			case "095ea7b3":
-				method = "approve"
-			}
-		}
+		// Определяем метод и аргументы по ABI и базе сигнатур
+		method, methodID, signature, args := ca.DecodeMethod(tx.Data())
 
 		// Создаем запись транзакции
 		transaction := models.ContractTransaction{
@@ -384,8 +459,11 @@ func (ca *ContractAnalyzer) GetContractTransactions(ctx context.Context, contrac
 			TransactionHash: log.TxHash.Hex(),
 			From:            from.Hex(),
 			To:              tx.To().Hex(),
-			Value:           tx.Value().String(),
+			Value:           models.NewBigInt(tx.Value()),
 			Method:          method,
+			MethodID:        methodID,
+			MethodSignature: signature,
+			DecodedArgs:     args,
 			BlockNumber:     log.BlockNumber,
 			Timestamp:       time.Unix(int64(block.Time()), 0),
 			GasUsed:         receipt.GasUsed,
@@ -399,7 +477,7 @@ func (ca *ContractAnalyzer) GetContractTransactions(ctx context.Context, contrac
 	// Сохраняем транзакции в БД
 	for _, tx := range transactions {
 		if err := repositories.DB.Create(&tx).Error; err != nil {
-			if !strings.Contains(err.Error(), "duplicate key") {
+			if !repositories.IsUniqueViolation(err) {
 				logrus.Errorf("Ошибка сохранения транзакции %s: %v", tx.TransactionHash, err)
 			}
 		}
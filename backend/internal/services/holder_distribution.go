@@ -0,0 +1,206 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	holderSnapshotInterval = time.Hour
+	holderTopEntries       = 100 // сколько крупнейших держателей сохраняется в снимке
+)
+
+// HolderDistribution периодически снимает распределение токена между держателями:
+// количество держателей, доли крупнейших, коэффициенты Джини и Накамото
+type HolderDistribution struct {
+	holderRepo    *repositories.HolderRepository
+	reconcileRepo *repositories.ReconciliationRepository
+	tokenAddress  common.Address
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+func NewHolderDistribution(holderRepo *repositories.HolderRepository, reconcileRepo *repositories.ReconciliationRepository, tokenAddress common.Address) *HolderDistribution {
+	return &HolderDistribution{
+		holderRepo:    holderRepo,
+		reconcileRepo: reconcileRepo,
+		tokenAddress:  tokenAddress,
+	}
+}
+
+func (h *HolderDistribution) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	h.cancel = cancel
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		ticker := time.NewTicker(holderSnapshotInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := h.Snapshot(h.tokenAddress.Hex()); err != nil {
+					logrus.Errorf("Ошибка снимка распределения держателей: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (h *HolderDistribution) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+		h.wg.Wait()
+	}
+}
+
+// Snapshot считает распределение токена на последнем проиндексированном блоке и сохраняет снимок.
+// Балансы берутся из леджера, если он ведется по токену, иначе из token_balances
+func (h *HolderDistribution) Snapshot(contractAddress string) (*models.HolderDistributionSnapshot, error) {
+	block, err := h.reconcileRepo.GetLastProcessedBlock()
+	if err != nil {
+		return nil, err
+	}
+	if block == 0 {
+		return nil, nil
+	}
+
+	fromLedger, err := h.holderRepo.HasLedgerHistory(contractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []repositories.HolderBalance
+	if fromLedger {
+		balances, err = h.holderRepo.GetLedgerHolderBalances(contractAddress, block)
+	} else {
+		balances, err = h.holderRepo.GetStoredHolderBalances(contractAddress)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения балансов держателей: %v", err)
+	}
+
+	snapshot := buildDistribution(balances)
+	snapshot.ContractAddress = contractAddress
+	snapshot.BlockNumber = block
+	snapshot.FromLedger = fromLedger
+	snapshot.SnapshotAt = time.Now()
+
+	created, err := h.holderRepo.SaveSnapshot(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения снимка распределения: %v", err)
+	}
+	if created {
+		logrus.Infof("Распределение %s на блоке #%d: держателей %d, топ-10 %.2f%%, Джини %.4f, Накамото %d",
+			contractAddress, block, snapshot.HolderCount, snapshot.Top10Share*100, snapshot.Gini, snapshot.Nakamoto)
+	}
+
+	return snapshot, nil
+}
+
+// GetLatest возвращает последний снимок распределения токена
+func (h *HolderDistribution) GetLatest(contractAddress string) (*models.HolderDistributionSnapshot, error) {
+	return h.holderRepo.GetLatestSnapshot(contractAddress)
+}
+
+// GetAtBlock возвращает снимок распределения, действовавший на блоке
+func (h *HolderDistribution) GetAtBlock(contractAddress string, block uint64) (*models.HolderDistributionSnapshot, error) {
+	return h.holderRepo.GetSnapshotAtBlock(contractAddress, block)
+}
+
+// GetHistory возвращает метрики распределения за период - в том числе количество держателей во времени
+func (h *HolderDistribution) GetHistory(contractAddress string, from, to time.Time) ([]models.HolderDistributionSnapshot, error) {
+	return h.holderRepo.GetSnapshotHistory(contractAddress, from, to)
+}
+
+// GetTopHolders возвращает limit крупнейших держателей из последнего снимка (не более holderTopEntries)
+func (h *HolderDistribution) GetTopHolders(contractAddress string, limit int) ([]models.HolderDistributionEntry, error) {
+	snapshot, err := h.holderRepo.GetLatestSnapshot(contractAddress)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	if limit > 0 && limit < len(snapshot.TopHolders) {
+		return snapshot.TopHolders[:limit], nil
+	}
+	return snapshot.TopHolders, nil
+}
+
+// buildDistribution считает метрики по ненулевым балансам, отсортированным от крупных к мелким
+func buildDistribution(balances []repositories.HolderBalance) *models.HolderDistributionSnapshot {
+	snapshot := &models.HolderDistributionSnapshot{HolderCount: uint64(len(balances))}
+
+	total := big.NewInt(0)
+	for _, holder := range balances {
+		total.Add(total, holder.Balance.Int())
+	}
+	snapshot.TotalBalance = models.NewBigInt(total)
+	if total.Sign() == 0 {
+		return snapshot
+	}
+
+	// Gini = 2·Σ(i·x_i) / (n·Σx) - (n+1)/n, где i - ранг по возрастанию баланса
+	n := int64(len(balances))
+	weighted := big.NewInt(0)
+	cumulative := big.NewInt(0)
+	top10, top100 := big.NewInt(0), big.NewInt(0)
+	for i, holder := range balances {
+		balance := holder.Balance.Int()
+		rankAscending := big.NewInt(n - int64(i))
+		weighted.Add(weighted, new(big.Int).Mul(rankAscending, balance))
+
+		if i < 10 {
+			top10.Add(top10, balance)
+		}
+		if i < 100 {
+			top100.Add(top100, balance)
+		}
+
+		cumulative.Add(cumulative, balance)
+		if snapshot.Nakamoto == 0 && new(big.Int).Lsh(cumulative, 1).Cmp(total) > 0 {
+			snapshot.Nakamoto = uint64(i + 1)
+		}
+
+		if i < holderTopEntries {
+			snapshot.TopHolders = append(snapshot.TopHolders, models.HolderDistributionEntry{
+				Rank:    uint(i + 1),
+				Address: holder.Address,
+				Balance: holder.Balance,
+				Share:   shareOf(balance, total),
+			})
+		}
+	}
+
+	gini := new(big.Rat).SetFrac(new(big.Int).Lsh(weighted, 1), new(big.Int).Mul(big.NewInt(n), total))
+	gini.Sub(gini, big.NewRat(n+1, n))
+	snapshot.Gini, _ = gini.Float64()
+	if snapshot.Gini < 0 {
+		snapshot.Gini = 0
+	}
+
+	snapshot.Top10Share = shareOf(top10, total)
+	snapshot.Top100Share = shareOf(top100, total)
+	return snapshot
+}
+
+// shareOf возвращает долю part от total в диапазоне 0..1
+func shareOf(part, total *big.Int) float64 {
+	if total.Sign() == 0 {
+		return 0
+	}
+	share, _ := new(big.Rat).SetFrac(part, total).Float64()
+	return share
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func holders(balances ...int64) []repositories.HolderBalance {
+	result := make([]repositories.HolderBalance, len(balances))
+	for i, balance := range balances {
+		result[i] = repositories.HolderBalance{
+			Address: fmt.Sprintf("0x%040d", i+1),
+			Balance: models.NewBigInt(big.NewInt(balance)),
+		}
+	}
+	return result
+}
+
+func TestBuildDistribution(t *testing.T) {
+	tests := []struct {
+		name       string
+		balances   []repositories.HolderBalance
+		gini       float64
+		nakamoto   uint64
+		top10Share float64
+	}{
+		{"нет держателей", holders(), 0, 0, 0},
+		{"нулевой общий баланс", holders(0), 0, 0, 0},
+		{"один держатель", holders(100), 0, 1, 1},
+		{"равные балансы", holders(10, 10, 10, 10), 0, 3, 1},
+		{"ровно половина у крупнейшего", holders(1, 1), 0, 2, 1},
+		{"больше половины у крупнейшего", holders(3, 1), 0.25, 1, 1},
+		{"высокая концентрация", holders(97, 1, 1, 1), 0.72, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := buildDistribution(tt.balances)
+
+			if snapshot.HolderCount != uint64(len(tt.balances)) {
+				t.Errorf("HolderCount = %d, ожидалось %d", snapshot.HolderCount, len(tt.balances))
+			}
+			if math.Abs(snapshot.Gini-tt.gini) > 1e-9 {
+				t.Errorf("Gini = %v, ожидалось %v", snapshot.Gini, tt.gini)
+			}
+			if snapshot.Nakamoto != tt.nakamoto {
+				t.Errorf("Nakamoto = %d, ожидалось %d", snapshot.Nakamoto, tt.nakamoto)
+			}
+			if math.Abs(snapshot.Top10Share-tt.top10Share) > 1e-9 {
+				t.Errorf("Top10Share = %v, ожидалось %v", snapshot.Top10Share, tt.top10Share)
+			}
+		})
+	}
+}
+
+func TestBuildDistributionTopHolders(t *testing.T) {
+	balances := make([]int64, holderTopEntries+50)
+	for i := range balances {
+		balances[i] = 1
+	}
+
+	snapshot := buildDistribution(holders(balances...))
+
+	if len(snapshot.TopHolders) != holderTopEntries {
+		t.Fatalf("TopHolders = %d записей, ожидалось %d", len(snapshot.TopHolders), holderTopEntries)
+	}
+	if first := snapshot.TopHolders[0]; first.Rank != 1 || first.Address != fmt.Sprintf("0x%040d", 1) {
+		t.Errorf("первый держатель: ранг %d, адрес %s", first.Rank, first.Address)
+	}
+
+	total := float64(len(balances))
+	if want := 10 / total; math.Abs(snapshot.Top10Share-want) > 1e-9 {
+		t.Errorf("Top10Share = %v, ожидалось %v", snapshot.Top10Share, want)
+	}
+	if want := float64(holderTopEntries) / total; math.Abs(snapshot.Top100Share-want) > 1e-9 {
+		t.Errorf("Top100Share = %v, ожидалось %v", snapshot.Top100Share, want)
+	}
+	if want := uint64(len(balances)/2 + 1); snapshot.Nakamoto != want {
+		t.Errorf("Nakamoto = %d, ожидалось %d", snapshot.Nakamoto, want)
+	}
+}
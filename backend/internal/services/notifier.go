@@ -3,6 +3,7 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repositories"
+	"backend/internal/utils"
 	"context"
 	"fmt"
 	"os"
@@ -89,13 +90,13 @@ func (n *Notifier) Start(ctx context.Context) error {
 	go func() {
 		defer n.wg.Done()
 
-		// Ждем начала следующего 15-секундного периода
-		now := time.Now()
-		nextPeriod := now.Truncate(15 * time.Second).Add(15 * time.Second)
+		// Ждем начала следующего периода активности
+		period := utils.ActivityPeriod()
+		nextPeriod := utils.GetCurrentPeriodStart().Add(period)
 		time.Sleep(time.Until(nextPeriod))
 
 		// Создаем тикер, который будет срабатывать точно в начале каждого периода
-		ticker := time.NewTicker(15 * time.Second)
+		ticker := time.NewTicker(period)
 		defer ticker.Stop()
 
 		// Сразу обрабатываем активность при старте
@@ -132,13 +133,14 @@ func (n *Notifier) Stop() {
 
 func (n *Notifier) processNewActivities() error {
 	// Получаем время начала текущего периода
-	currentPeriod := time.Now().Truncate(15 * time.Second)
+	period := utils.ActivityPeriod()
+	currentPeriod := utils.GetCurrentPeriodStart()
 
 	// Получаем новые записи активности только за последние два периода
 	var activities []models.AccountActivity
 	if err := n.db.Where("period >= ? AND period < ?",
-		currentPeriod.Add(-15*time.Second), // предыдущий период
-		currentPeriod.Add(15*time.Second),  // следующий период
+		currentPeriod.Add(-period), // предыдущий период
+		currentPeriod.Add(period),  // следующий период
 	).Order("id ASC").Find(&activities).Error; err != nil {
 		return fmt.Errorf("ошибка получения активности: %v", err)
 	}
@@ -177,14 +179,32 @@ func (n *Notifier) SendMessageTelegram(activity *models.AccountActivity) error {
 			"⏰ Период: %v\n"+
 			"🔄 Транзакции: %d\n"+
 			"🔁 Токен-трансферы: %d\n"+
-			"💰 Объем (ETH): %s",
+			"💰 Объем: %s\n"+
+			"📥 Входящие: %d транзакций, %d токен-трансферов, %s\n"+
+			"⚖️ Чистый поток: %s\n"+
+			"👥 Контрагенты: %d",
 		activity.Address,
 		activity.Period.Format("2006-01-02 15:04:05"),
 		activity.TransactionCount,
 		activity.TokenTransfers,
-		activity.GetVolumeETH().String(),
+		utils.FormatETH(activity.VolumeETH.Int()),
+		activity.ReceivedCount,
+		activity.TokenTransfersReceived,
+		utils.FormatETH(activity.VolumeETHReceived.Int()),
+		utils.FormatETH(activity.GetNetFlowETH()),
+		activity.Counterparties,
 	)
 
+	return n.sendTelegram(message)
+}
+
+// SendAlert отправляет произвольное предупреждение в Telegram и лог уведомлений
+func (n *Notifier) SendAlert(message string) error {
+	n.notifLog.Printf("ALERT: %s", message)
+	return n.sendTelegram(message)
+}
+
+func (n *Notifier) sendTelegram(message string) error {
 	if n.bot != nil && n.chatID != "" {
 		chatID, err := strconv.ParseInt(n.chatID, 10, 64)
 		if err != nil {
@@ -204,11 +224,18 @@ func (n *Notifier) SendMessageTelegram(activity *models.AccountActivity) error {
 }
 
 func (n *Notifier) MakeNotificationLog(activity *models.AccountActivity) error {
-	n.notifLog.Printf("Address: %s, Period: %v, TransactionCount: %d, TokenTransfers: %d",
+	n.notifLog.Printf("Address: %s, Period: %v, TransactionCount: %d, TokenTransfers: %d, Volume: %s, "+
+		"ReceivedCount: %d, TokenTransfersReceived: %d, VolumeReceived: %s, NetFlow: %s, Counterparties: %d",
 		activity.Address,
 		activity.Period,
 		activity.TransactionCount,
-		activity.TokenTransfers)
+		activity.TokenTransfers,
+		utils.FormatETH(activity.VolumeETH.Int()),
+		activity.ReceivedCount,
+		activity.TokenTransfersReceived,
+		utils.FormatETH(activity.VolumeETHReceived.Int()),
+		utils.FormatETH(activity.GetNetFlowETH()),
+		activity.Counterparties)
 
 	return nil
 }
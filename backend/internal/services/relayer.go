@@ -0,0 +1,323 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/contracts"
+	"backend/pkg/ethereum"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	relayInterval         = 5 * time.Second
+	defaultRelayBatchSize = 20
+	maxRelayAttempts      = 5
+	relayRetryBaseDelay   = 30 * time.Second
+	relaySentTimeout      = 10 * time.Minute // сколько ждать включения в блок транзакции, которую менеджер больше не отслеживает
+)
+
+// Relayer ретранслирует внешние транзакции отслеживаемых адресов в AnalyzerToken.recordExternalTransaction
+type Relayer struct {
+	ethClient    *ethereum.Client
+	txManager    *ethereum.TxManager
+	relayRepo    *repositories.RelayRepository
+	tokenABI     abi.ABI
+	tokenAddress common.Address
+	addresses    map[string]bool
+	batchSize    int
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+func NewRelayer(ethClient *ethereum.Client, txManager *ethereum.TxManager, relayRepo *repositories.RelayRepository, tokenAddress common.Address, addresses []string, batchSize int) (*Relayer, error) {
+	tokenABI, err := contracts.LoadAnalyzerTokenABI()
+	if err != nil {
+		return nil, err
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultRelayBatchSize
+	}
+
+	watched := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		watched[common.HexToAddress(address).Hex()] = true
+	}
+
+	return &Relayer{
+		ethClient:    ethClient,
+		txManager:    txManager,
+		relayRepo:    relayRepo,
+		tokenABI:     tokenABI,
+		tokenAddress: tokenAddress,
+		addresses:    watched,
+		batchSize:    batchSize,
+	}, nil
+}
+
+func (r *Relayer) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(relayInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logrus.Info("Остановка ретранслятора внешних транзакций")
+				return
+			case <-ticker.C:
+				if err := r.checkSent(ctx); err != nil {
+					logrus.Errorf("Ошибка проверки отправленных ретрансляций: %v", err)
+				}
+				if err := r.sendBatch(ctx); err != nil {
+					logrus.Errorf("Ошибка отправки ретрансляций: %v", err)
+				}
+			}
+		}
+	}()
+
+	logrus.Infof("Ретранслятор внешних транзакций запущен для %d адресов", len(r.addresses))
+	return nil
+}
+
+func (r *Relayer) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+}
+
+// Enqueue ставит в очередь транзакцию, если в ней участвует отслеживаемый адрес
+func (r *Relayer) Enqueue(tx *models.Transaction, input []byte) error {
+	if !r.addresses[tx.From] && !r.addresses[tx.To] {
+		return nil
+	}
+
+	// Собственные транзакции ретранслятора не записываем, иначе получим бесконечный цикл
+	if tx.From == r.ethClient.GetAddress().Hex() && tx.To == r.tokenAddress.Hex() {
+		return nil
+	}
+
+	relay := &models.ExternalTransactionRelay{
+		TransactionHash: tx.Hash,
+		From:            tx.From,
+		To:              tx.To,
+		Value:           tx.Value,
+		MethodID:        methodSelector(input),
+		Success:         tx.IsSuccessful(),
+		GasUsed:         tx.GasUsed,
+		BlockNumber:     tx.BlockNumber,
+		Status:          models.RelayStatusPending,
+	}
+
+	if err := r.relayRepo.Enqueue(relay); err != nil {
+		return fmt.Errorf("ошибка постановки в очередь ретрансляции %s: %v", tx.Hash, err)
+	}
+
+	logrus.Debugf("Транзакция %s поставлена в очередь ретрансляции", tx.Hash)
+	return nil
+}
+
+// sendBatch отправляет очередную пачку записей из очереди
+func (r *Relayer) sendBatch(ctx context.Context) error {
+	relays, err := r.relayRepo.GetReadyToSend(r.batchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range relays {
+		relay := &relays[i]
+
+		data, err := r.packRecord(relay)
+		if err != nil {
+			// Данные некорректны - повтор не поможет
+			if markErr := r.relayRepo.MarkFailed(relay.ID, err.Error()); markErr != nil {
+				logrus.Errorf("Ошибка обновления статуса ретрансляции %s: %v", relay.TransactionHash, markErr)
+			}
+			continue
+		}
+
+		tx, err := r.txManager.Send(ctx, r.tokenAddress, nil, data)
+		if err != nil {
+			r.scheduleRetry(relay, err)
+			continue
+		}
+
+		if err := r.relayRepo.MarkSent(relay.ID, tx.Hash().Hex(), tx.Nonce()); err != nil {
+			logrus.Errorf("Ошибка обновления статуса ретрансляции %s: %v", relay.TransactionHash, err)
+		}
+	}
+
+	if len(relays) > 0 {
+		logrus.Infof("Отправлено на ретрансляцию %d внешних транзакций", len(relays))
+	}
+	return nil
+}
+
+// checkSent проверяет receipt отправленных ретрансляций
+func (r *Relayer) checkSent(ctx context.Context) error {
+	relays, err := r.relayRepo.GetSent()
+	if err != nil {
+		return err
+	}
+
+	if len(relays) == 0 {
+		return nil
+	}
+
+	// Все nonce ниже подтвержденного уже заняты транзакциями в блоках
+	confirmedNonce, err := r.ethClient.GetClient().NonceAt(ctx, r.ethClient.GetAddress(), nil)
+	if err != nil {
+		return fmt.Errorf("ошибка получения nonce: %v", err)
+	}
+
+	for i := range relays {
+		relay := &relays[i]
+		hash := relay.RelayTxHash
+
+		receipt, err := r.findReceipt(ctx, relay)
+		if err != nil {
+			return err
+		}
+
+		if receipt == nil {
+			r.checkUnmined(relay, confirmedNonce)
+			continue
+		}
+
+		// В блок могла попасть замена с повышенной комиссией, а не исходная транзакция
+		if receipt.TxHash.Hex() != hash {
+			hash = receipt.TxHash.Hex()
+			if err := r.relayRepo.UpdateRelayTxHash(relay.ID, hash); err != nil {
+				logrus.Errorf("Ошибка обновления хеша ретрансляции %s: %v", relay.TransactionHash, err)
+			}
+		}
+
+		if receipt.Status == 1 {
+			if err := r.relayRepo.MarkConfirmed(relay.ID); err != nil {
+				logrus.Errorf("Ошибка обновления статуса ретрансляции %s: %v", relay.TransactionHash, err)
+			}
+			continue
+		}
+
+		r.scheduleRetry(relay, fmt.Errorf("транзакция ретрансляции %s откатилась", hash))
+	}
+
+	return nil
+}
+
+// findReceipt ищет receipt ретрансляции среди всех версий транзакции, отправленных с ее nonce.
+// Возвращает nil без ошибки, если ни одна версия еще не включена в блок
+func (r *Relayer) findReceipt(ctx context.Context, relay *models.ExternalTransactionRelay) (*types.Receipt, error) {
+	hash := common.HexToHash(relay.RelayTxHash)
+	if relay.RelayNonce != nil {
+		return r.txManager.FindReceipt(ctx, *relay.RelayNonce, hash)
+	}
+
+	receipt, err := r.ethClient.GetClient().TransactionReceipt(ctx, hash)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, eth.NotFound) {
+			logrus.Debugf("Ошибка получения receipt ретрансляции %s: %v", hash.Hex(), err)
+		}
+		return nil, nil
+	}
+	return receipt, nil
+}
+
+// checkUnmined решает судьбу ретрансляции, ни одна версия которой не найдена в блоках.
+// Пока менеджер отслеживает транзакцию и заменяет ее при зависании, ждем; иначе после relaySentTimeout
+// повторяем отправку, а если nonce уже занят неизвестной транзакцией - помечаем запись неуспешной,
+// чтобы не записать внешнюю транзакцию в контракт дважды
+func (r *Relayer) checkUnmined(relay *models.ExternalTransactionRelay, confirmedNonce uint64) {
+	if relay.RelayNonce != nil {
+		if _, tracked := r.txManager.PendingByNonce(*relay.RelayNonce); tracked {
+			return
+		}
+	}
+
+	sentAt := relay.UpdatedAt
+	if relay.SentAt != nil {
+		sentAt = *relay.SentAt
+	}
+	if time.Since(sentAt) < relaySentTimeout {
+		return
+	}
+
+	if relay.RelayNonce != nil && *relay.RelayNonce < confirmedNonce {
+		cause := fmt.Sprintf("nonce %d занят транзакцией, неизвестной ретранслятору: проверьте запись в контракте вручную", *relay.RelayNonce)
+		logrus.Errorf("Ретрансляция %s: %s", relay.TransactionHash, cause)
+		if err := r.relayRepo.MarkFailed(relay.ID, cause); err != nil {
+			logrus.Errorf("Ошибка обновления статуса ретрансляции %s: %v", relay.TransactionHash, err)
+		}
+		return
+	}
+
+	r.scheduleRetry(relay, fmt.Errorf("транзакция ретрансляции %s не включена в блок за %v", relay.RelayTxHash, relaySentTimeout))
+}
+
+// scheduleRetry откладывает повторную попытку с экспоненциальной задержкой или помечает запись неуспешной
+func (r *Relayer) scheduleRetry(relay *models.ExternalTransactionRelay, cause error) {
+	attempts := relay.Attempts + 1
+	if attempts >= maxRelayAttempts {
+		logrus.Errorf("Ретрансляция %s не удалась после %d попыток: %v", relay.TransactionHash, attempts, cause)
+		if err := r.relayRepo.MarkFailed(relay.ID, cause.Error()); err != nil {
+			logrus.Errorf("Ошибка обновления статуса ретрансляции %s: %v", relay.TransactionHash, err)
+		}
+		return
+	}
+
+	delay := relayRetryBaseDelay * time.Duration(1<<(attempts-1))
+	logrus.Warnf("Ретрансляция %s будет повторена через %v: %v", relay.TransactionHash, delay, cause)
+	if err := r.relayRepo.MarkRetry(relay.ID, cause.Error(), time.Now().Add(delay)); err != nil {
+		logrus.Errorf("Ошибка обновления статуса ретрансляции %s: %v", relay.TransactionHash, err)
+	}
+}
+
+// packRecord упаковывает вызов recordExternalTransaction
+func (r *Relayer) packRecord(relay *models.ExternalTransactionRelay) ([]byte, error) {
+	selectorBytes, err := hex.DecodeString(strings.TrimPrefix(relay.MethodID, "0x"))
+	if err != nil || len(selectorBytes) != 4 {
+		return nil, fmt.Errorf("неверный селектор метода %q", relay.MethodID)
+	}
+
+	var selector [4]byte
+	copy(selector[:], selectorBytes)
+
+	return r.tokenABI.Pack("recordExternalTransaction",
+		common.HexToAddress(relay.From),
+		common.HexToAddress(relay.To),
+		relay.Value.Int(),
+		selector,
+		relay.Success,
+		new(big.Int).SetUint64(relay.GasUsed),
+	)
+}
+
+// methodSelector возвращает селектор метода из input данных (0x00000000 для простых переводов)
+func methodSelector(input []byte) string {
+	if len(input) < 4 {
+		return "0x00000000"
+	}
+	return "0x" + hex.EncodeToString(input[:4])
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/contracts"
+	"backend/pkg/ethereum"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	statsReconcileInterval   = 10 * time.Minute
+	reconcilePeriodWindow    = 24 * time.Hour // окно сверки getVolumeForPeriod
+	defaultDriftThresholdPct = 1.0
+	maxAlertLines            = 10
+)
+
+// StatsReconciler сверяет статистику AnalyzerToken (getExtendedAccountStats, getVolumeForPeriod)
+// с AccountStats и данными, проиндексированными в БД, и сохраняет отчет о расхождениях
+type StatsReconciler struct {
+	ethClient      *ethereum.Client
+	reconcileRepo  *repositories.ReconciliationRepository
+	notifier       *Notifier
+	tokenRegistry  *TokenRegistry
+	token          *contracts.AnalyzerTokenCaller
+	tokenAddress   common.Address
+	driftThreshold float64 // порог дрейфа в процентах для оповещения
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+}
+
+func NewStatsReconciler(ethClient *ethereum.Client, reconcileRepo *repositories.ReconciliationRepository, notifier *Notifier, tokenRegistry *TokenRegistry, tokenAddress common.Address, driftThreshold float64) (*StatsReconciler, error) {
+	token, err := contracts.NewAnalyzerTokenCaller(tokenAddress, ethClient.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания биндинга AnalyzerToken: %v", err)
+	}
+
+	// Ноль - оповещать о любом расхождении, отрицательное значение - порог не задан
+	if driftThreshold < 0 {
+		driftThreshold = defaultDriftThresholdPct
+	}
+
+	return &StatsReconciler{
+		ethClient:      ethClient,
+		reconcileRepo:  reconcileRepo,
+		notifier:       notifier,
+		tokenRegistry:  tokenRegistry,
+		token:          token,
+		tokenAddress:   tokenAddress,
+		driftThreshold: driftThreshold,
+	}, nil
+}
+
+func (r *StatsReconciler) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(statsReconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.Reconcile(ctx); err != nil {
+					logrus.Errorf("Ошибка сверки статистики с контрактом: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *StatsReconciler) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+}
+
+// Reconcile выполняет сверку всех участников токена на последнем проиндексированном блоке
+func (r *StatsReconciler) Reconcile(ctx context.Context) ([]models.StatsDiscrepancy, error) {
+	block, err := r.reconcileRepo.GetLastProcessedBlock()
+	if err != nil {
+		return nil, err
+	}
+	if block == 0 {
+		return nil, nil
+	}
+
+	// Читаем контракт на том же блоке, до которого проиндексирована БД
+	header, err := r.ethClient.GetClient().HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения блока #%d: %v", block, err)
+	}
+	blockTime := time.Unix(int64(header.Time), 0)
+	from := blockTime.Add(-reconcilePeriodWindow)
+
+	addresses, err := r.reconcileRepo.GetTokenParticipants(r.tokenAddress.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	accountStats, err := r.reconcileRepo.GetAccountStats(addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]common.Address, len(addresses))
+	for i, address := range addresses {
+		users[i] = common.HexToAddress(address)
+	}
+
+	onChainStats, err := r.fetchOnChainStats(ctx, users, header.Number, from, blockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	runID := fmt.Sprintf("%x", time.Now().UnixNano())
+	checkedAt := time.Now()
+
+	var report []models.StatsDiscrepancy
+	for i, address := range addresses {
+		onChain, ok := onChainStats[users[i]]
+		if !ok {
+			logrus.Errorf("Ошибка сверки аккаунта %s: контракт не вернул статистику", address)
+			continue
+		}
+
+		discrepancies, err := r.reconcileAccount(address, onChain, accountStats[address], block, from, blockTime)
+		if err != nil {
+			logrus.Errorf("Ошибка сверки аккаунта %s: %v", address, err)
+			continue
+		}
+
+		for j := range discrepancies {
+			discrepancies[j].RunID = runID
+			discrepancies[j].ContractAddress = r.tokenAddress.Hex()
+			discrepancies[j].Address = address
+			discrepancies[j].BlockNumber = block
+			discrepancies[j].CheckedAt = checkedAt
+		}
+		report = append(report, discrepancies...)
+	}
+
+	if err := r.reconcileRepo.SaveDiscrepancies(report); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения отчета сверки: %v", err)
+	}
+
+	logrus.Infof("Сверка статистики на блоке #%d: проверено %d аккаунтов, расхождений %d",
+		block, len(addresses), len(report))
+
+	r.alert(report, block)
+	return report, nil
+}
+
+// onChainAccountStats - статистика аккаунта, прочитанная из AnalyzerToken
+type onChainAccountStats struct {
+	TotalTx        *big.Int
+	TotalSent      *big.Int
+	TotalReceived  *big.Int
+	ExternalTx     *big.Int
+	PeriodSent     *big.Int
+	PeriodReceived *big.Int
+}
+
+// fetchOnChainStats читает getExtendedAccountStats и getVolumeForPeriod через Multicall3 (по два вызова на аккаунт),
+// а если он не задеплоен - по одному вызову на аккаунт. Аккаунты, по которым вызов не удался, отсутствуют в результате
+func (r *StatsReconciler) fetchOnChainStats(ctx context.Context, users []common.Address, blockNumber *big.Int, from, to time.Time) (map[common.Address]*onChainAccountStats, error) {
+	fromTs, toTs := big.NewInt(from.Unix()), big.NewInt(to.Unix())
+
+	multicall, err := r.ethClient.GetMulticall()
+	if err == nil {
+		deployed, err := multicall.IsDeployed(ctx)
+		if err == nil && deployed {
+			return r.fetchOnChainStatsBatched(ctx, multicall, users, blockNumber, fromTs, toTs)
+		}
+	}
+
+	logrus.Debug("Multicall3 недоступен, читаем статистику контракта по одному аккаунту")
+
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: blockNumber}
+	stats := make(map[common.Address]*onChainAccountStats, len(users))
+	for _, user := range users {
+		extended, err := r.token.GetExtendedAccountStats(opts, user)
+		if err != nil {
+			logrus.Debugf("Ошибка чтения getExtendedAccountStats для %s: %v", user.Hex(), err)
+			continue
+		}
+
+		period, err := r.token.GetVolumeForPeriod(opts, user, fromTs, toTs)
+		if err != nil {
+			logrus.Debugf("Ошибка чтения getVolumeForPeriod для %s: %v", user.Hex(), err)
+			continue
+		}
+
+		stats[user] = &onChainAccountStats{
+			TotalTx:        extended.TotalTx,
+			TotalSent:      extended.TotalSent,
+			TotalReceived:  extended.TotalReceived,
+			ExternalTx:     extended.ExternalTx,
+			PeriodSent:     period.Sent,
+			PeriodReceived: period.Received,
+		}
+	}
+
+	return stats, nil
+}
+
+func (r *StatsReconciler) fetchOnChainStatsBatched(ctx context.Context, multicall *contracts.Multicall, users []common.Address, blockNumber, fromTs, toTs *big.Int) (map[common.Address]*onChainAccountStats, error) {
+	tokenABI, err := contracts.LoadAnalyzerTokenABI()
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]contracts.Call, 0, len(users)*2)
+	for _, user := range users {
+		calls = append(calls,
+			contracts.Call{Target: r.tokenAddress, ABI: tokenABI, Method: "getExtendedAccountStats", Args: []interface{}{user}, AllowFailure: true},
+			contracts.Call{Target: r.tokenAddress, ABI: tokenABI, Method: "getVolumeForPeriod", Args: []interface{}{user, fromTs, toTs}, AllowFailure: true},
+		)
+	}
+
+	results, err := multicall.Aggregate(ctx, calls, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[common.Address]*onChainAccountStats, len(users))
+	for i, user := range users {
+		extended, period := results[2*i], results[2*i+1]
+		if extended.Err != nil || period.Err != nil {
+			logrus.Debugf("Ошибка чтения статистики контракта для %s: %v %v", user.Hex(), extended.Err, period.Err)
+			continue
+		}
+		if len(extended.Outputs) < 6 || len(period.Outputs) < 2 {
+			continue
+		}
+
+		stats[user] = &onChainAccountStats{
+			TotalTx:        extended.Outputs[0].(*big.Int),
+			TotalSent:      extended.Outputs[1].(*big.Int),
+			TotalReceived:  extended.Outputs[2].(*big.Int),
+			ExternalTx:     extended.Outputs[5].(*big.Int),
+			PeriodSent:     period.Outputs[0].(*big.Int),
+			PeriodReceived: period.Outputs[1].(*big.Int),
+		}
+	}
+
+	return stats, nil
+}
+
+// reconcileAccount сравнивает метрики одного аккаунта и возвращает только расхождения
+func (r *StatsReconciler) reconcileAccount(address string, onChain *onChainAccountStats, stats *models.AccountStats, block uint64, from, to time.Time) ([]models.StatsDiscrepancy, error) {
+	recorded, err := r.reconcileRepo.GetTokenTransferTotals(address, r.tokenAddress.Hex(), block)
+	if err != nil {
+		return nil, err
+	}
+
+	relayed, err := r.reconcileRepo.CountConfirmedRelays(address)
+	if err != nil {
+		return nil, err
+	}
+
+	periodSent, periodReceived, err := r.reconcileRepo.GetTokenVolumeForPeriod(address, r.tokenAddress.Hex(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareAccountStats(onChain, stats, recorded, relayed, periodSent, periodReceived), nil
+}
+
+// compareAccountStats строит расхождения между статистикой контракта и AccountStats.
+// Агрегаты сырых трансферов (recorded_*) сверяются дополнительно и помогают понять, где именно разошелся AccountStats
+func compareAccountStats(onChain *onChainAccountStats, stats *models.AccountStats, recorded *repositories.TokenTransferTotals, relayed uint64, periodSent, periodReceived *big.Int) []models.StatsDiscrepancy {
+	if stats == nil {
+		stats = &models.AccountStats{}
+	}
+
+	// Контракт считает исходящие и входящие переводы в одном счетчике
+	dbTransactions := new(big.Int).SetUint64(stats.ERC20Transactions + stats.ERC20TransactionsReceived)
+
+	comparisons := []models.StatsDiscrepancy{
+		models.NewStatsDiscrepancy(models.ReconcileMetricTotalTransactions, onChain.TotalTx, dbTransactions),
+		models.NewStatsDiscrepancy(models.ReconcileMetricTotalSent, onChain.TotalSent, stats.TokenVolumeSent.Int()),
+		models.NewStatsDiscrepancy(models.ReconcileMetricTotalReceived, onChain.TotalReceived, stats.TokenVolumeReceived.Int()),
+		models.NewStatsDiscrepancy(models.ReconcileMetricExternalTransactions, onChain.ExternalTx, new(big.Int).SetUint64(relayed)),
+		models.NewStatsDiscrepancy(models.ReconcileMetricPeriodSent, onChain.PeriodSent, periodSent),
+		models.NewStatsDiscrepancy(models.ReconcileMetricPeriodReceived, onChain.PeriodReceived, periodReceived),
+		models.NewStatsDiscrepancy(models.ReconcileMetricRecordedTransactions, onChain.TotalTx, new(big.Int).SetUint64(recorded.TransferCount)),
+		models.NewStatsDiscrepancy(models.ReconcileMetricRecordedSent, onChain.TotalSent, recorded.Sent),
+		models.NewStatsDiscrepancy(models.ReconcileMetricRecordedReceived, onChain.TotalReceived, recorded.Received),
+	}
+
+	var discrepancies []models.StatsDiscrepancy
+	for _, comparison := range comparisons {
+		if comparison.Difference.Sign() != 0 {
+			discrepancies = append(discrepancies, comparison)
+		}
+	}
+
+	return discrepancies
+}
+
+// alert оповещает о расхождениях, превышающих порог
+func (r *StatsReconciler) alert(report []models.StatsDiscrepancy, block uint64) {
+	if r.notifier == nil {
+		return
+	}
+
+	var lines []string
+	for _, d := range report {
+		if d.DriftPercent < r.driftThreshold {
+			continue
+		}
+		if len(lines) < maxAlertLines {
+			lines = append(lines, fmt.Sprintf("<code>%s</code> %s: контракт %s, БД %s (%.2f%%)",
+				d.Address, d.Metric, r.formatValue(d.Metric, d.OnChainValue), r.formatValue(d.Metric, d.DBValue), d.DriftPercent))
+		}
+	}
+
+	if len(lines) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ <b>Расхождение статистики с контрактом</b> (блок #%d, порог %.2f%%)\n\n%s",
+		block, r.driftThreshold, strings.Join(lines, "\n"))
+
+	if err := r.notifier.SendAlert(message); err != nil {
+		logrus.Errorf("Ошибка отправки оповещения о расхождениях: %v", err)
+	}
+}
+
+// formatValue форматирует значение метрики: суммы токена - с decimals и символом, счетчики - как есть
+func (r *StatsReconciler) formatValue(metric string, value models.BigInt) string {
+	switch metric {
+	case models.ReconcileMetricTotalTransactions, models.ReconcileMetricExternalTransactions, models.ReconcileMetricRecordedTransactions:
+		return value.String()
+	}
+	if r.tokenRegistry == nil {
+		return value.String()
+	}
+	return r.tokenRegistry.FormatAmount(r.tokenAddress.Hex(), value.Int())
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"math/big"
+	"testing"
+)
+
+func TestCompareAccountStats(t *testing.T) {
+	onChain := &onChainAccountStats{
+		TotalTx:        big.NewInt(5),
+		TotalSent:      big.NewInt(300),
+		TotalReceived:  big.NewInt(700),
+		ExternalTx:     big.NewInt(1),
+		PeriodSent:     big.NewInt(100),
+		PeriodReceived: big.NewInt(0),
+	}
+	recorded := &repositories.TokenTransferTotals{TransferCount: 5, Sent: big.NewInt(300), Received: big.NewInt(700)}
+
+	// AccountStats учитывает mint на 50: полученный объем и входящий счетчик больше, чем в контракте
+	stats := &models.AccountStats{
+		ERC20Transactions:         2,
+		ERC20TransactionsReceived: 4,
+		TokenVolumeSent:           models.NewBigInt(big.NewInt(300)),
+		TokenVolumeReceived:       models.NewBigInt(big.NewInt(750)),
+	}
+
+	got := compareAccountStats(onChain, stats, recorded, 1, big.NewInt(100), big.NewInt(0))
+	if len(got) != 2 {
+		t.Fatalf("ожидалось 2 расхождения, получено %d: %+v", len(got), got)
+	}
+	if got[0].Metric != models.ReconcileMetricTotalTransactions || got[0].DBValue.Int().Int64() != 6 || got[0].Difference.Int().Int64() != -1 {
+		t.Errorf("неверное расхождение счетчика: %+v", got[0])
+	}
+	if got[1].Metric != models.ReconcileMetricTotalReceived || got[1].Difference.Int().Int64() != -50 {
+		t.Errorf("неверное расхождение полученного объема: %+v", got[1])
+	}
+
+	// Без AccountStats вся статистика контракта - расхождение, сырые трансферы при этом сходятся
+	got = compareAccountStats(onChain, nil, recorded, 1, big.NewInt(100), big.NewInt(0))
+	for _, d := range got {
+		switch d.Metric {
+		case models.ReconcileMetricRecordedTransactions, models.ReconcileMetricRecordedSent, models.ReconcileMetricRecordedReceived:
+			t.Errorf("лишнее расхождение %s по сырым трансферам", d.Metric)
+		}
+		if d.DBValue.Sign() != 0 {
+			t.Errorf("%s: ожидался ноль в БД, получено %s", d.Metric, d.DBValue.String())
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("ожидалось 3 расхождения (total_*), получено %d", len(got))
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/contracts"
+	"backend/pkg/ethereum"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	supplySyncInterval = 5 * time.Minute
+	supplyVerifyBatch  = 100
+	supplySyncDebounce = 500 * time.Millisecond // задержка перед пересчетом после mint/burn, чтобы собрать пачку событий
+)
+
+// SupplyTracker ведет историю эмиссии токенов по событиям mint и burn
+// и сверяет ее с totalSupply контракта на тех же блоках
+type SupplyTracker struct {
+	ethClient     *ethereum.Client
+	supplyRepo    *repositories.SupplyRepository
+	notifier      *Notifier
+	tokenRegistry *TokenRegistry
+	trigger       chan struct{}
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+func NewSupplyTracker(ethClient *ethereum.Client, supplyRepo *repositories.SupplyRepository, notifier *Notifier, tokenRegistry *TokenRegistry) *SupplyTracker {
+	return &SupplyTracker{
+		ethClient:     ethClient,
+		supplyRepo:    supplyRepo,
+		notifier:      notifier,
+		tokenRegistry: tokenRegistry,
+		trigger:       make(chan struct{}, 1),
+	}
+}
+
+func (t *SupplyTracker) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	t.cancel = cancel
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(supplySyncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-t.trigger:
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(supplySyncDebounce):
+				}
+			}
+
+			if err := t.Sync(ctx); err != nil {
+				logrus.Errorf("Ошибка обновления истории эмиссии: %v", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (t *SupplyTracker) Stop() {
+	if t.cancel != nil {
+		t.cancel()
+		t.wg.Wait()
+	}
+}
+
+// Notify сообщает о новом mint или burn; пересчет выполняется асинхронно
+func (t *SupplyTracker) Notify() {
+	select {
+	case t.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Sync пересчитывает историю эмиссии всех токенов и сверяет новые записи с контрактом
+func (t *SupplyTracker) Sync(ctx context.Context) error {
+	contractAddresses, err := t.supplyRepo.GetSupplyContracts()
+	if err != nil {
+		return err
+	}
+
+	for _, contractAddress := range contractAddresses {
+		if err := t.ensureOpening(ctx, contractAddress); err != nil {
+			// Без начальной эмиссии каждая запись разошлась бы с контрактом на одну и ту же величину
+			logrus.Warnf("Не удалось получить начальную эмиссию %s, история будет пересчитана позже: %v", contractAddress, err)
+			continue
+		}
+
+		updated, err := t.supplyRepo.RebuildSupplyHistory(contractAddress)
+		if err != nil {
+			logrus.Errorf("Ошибка пересчета истории эмиссии %s: %v", contractAddress, err)
+			continue
+		}
+		if updated > 0 {
+			logrus.Debugf("История эмиссии %s: обновлено %d блоков", contractAddress, updated)
+		}
+
+		if err := t.verify(ctx, contractAddress); err != nil {
+			logrus.Errorf("Ошибка сверки эмиссии %s: %v", contractAddress, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureOpening сохраняет totalSupply контракта на блоке перед первым проиндексированным трансфером токена,
+// чтобы история была абсолютной, даже если индексация началась после деплоя токена
+func (t *SupplyTracker) ensureOpening(ctx context.Context, contractAddress string) error {
+	opening, err := t.supplyRepo.GetOpeningSupply(contractAddress)
+	if err != nil || opening != nil {
+		return err
+	}
+
+	firstBlock, err := t.supplyRepo.GetFirstTransferBlock(contractAddress)
+	if err != nil {
+		return err
+	}
+	if firstBlock == 0 {
+		return nil // индексация с генезиса - отсчет от нуля и так верен
+	}
+
+	previous := new(big.Int).SetUint64(firstBlock - 1)
+	address := common.HexToAddress(contractAddress)
+
+	// Токен, задеплоенный в проиндексированном диапазоне, до первого трансфера еще не существовал
+	code, err := t.ethClient.GetClient().CodeAt(ctx, address, previous)
+	if err != nil {
+		return fmt.Errorf("ошибка получения кода на блоке #%d: %v", previous.Uint64(), err)
+	}
+
+	supply := big.NewInt(0)
+	if len(code) > 0 {
+		token, err := contracts.NewERC20Contract(address, t.ethClient.GetClient())
+		if err != nil {
+			return err
+		}
+		supply, err = token.TotalSupplyAtBlock(ctx, previous)
+		if err != nil {
+			return fmt.Errorf("ошибка получения totalSupply на блоке #%d: %v", previous.Uint64(), err)
+		}
+	}
+
+	if err := t.supplyRepo.SaveOpeningSupply(contractAddress, previous.Uint64(), supply); err != nil {
+		return err
+	}
+
+	logrus.Infof("Начальная эмиссия %s на блоке #%d: %s", contractAddress, previous.Uint64(), t.formatAmount(contractAddress, supply))
+	return nil
+}
+
+// verify сверяет непроверенные записи истории с totalSupply контракта на их блоках
+func (t *SupplyTracker) verify(ctx context.Context, contractAddress string) error {
+	snapshots, err := t.supplyRepo.GetUnverifiedSnapshots(contractAddress, supplyVerifyBatch)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	token, err := contracts.NewERC20Contract(common.HexToAddress(contractAddress), t.ethClient.GetClient())
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	for _, snapshot := range snapshots {
+		onChain, err := token.TotalSupplyAtBlock(ctx, new(big.Int).SetUint64(snapshot.BlockNumber))
+		if err != nil {
+			// Без архивной ноды состояние старых блоков недоступно - попробуем в следующий раз
+			logrus.Debugf("Не удалось получить totalSupply %s на блоке #%d: %v", contractAddress, snapshot.BlockNumber, err)
+			continue
+		}
+
+		indexed := snapshot.TotalSupply.Int()
+		matches := onChain.Cmp(indexed) == 0
+		if err := t.supplyRepo.MarkVerified(snapshot.ID, onChain, matches); err != nil {
+			return err
+		}
+
+		if !matches {
+			// Разница выводится в минимальных единицах: после округления суммы могут выглядеть одинаково
+			difference := new(big.Int).Sub(onChain, indexed)
+			logrus.Warnf("Эмиссия %s на блоке #%d не совпадает: контракт %s, события %s (разница %s)",
+				contractAddress, snapshot.BlockNumber, t.formatAmount(contractAddress, onChain),
+				t.formatAmount(contractAddress, indexed), difference.String())
+			if len(mismatches) < maxAlertLines {
+				mismatches = append(mismatches, fmt.Sprintf("блок #%d: контракт %s, события %s (разница %s)",
+					snapshot.BlockNumber, t.formatAmount(contractAddress, onChain),
+					t.formatAmount(contractAddress, indexed), difference.String()))
+			}
+		}
+	}
+
+	t.alert(contractAddress, mismatches)
+	return nil
+}
+
+// formatAmount форматирует сумму токена, если реестр подключен
+func (t *SupplyTracker) formatAmount(contractAddress string, value *big.Int) string {
+	if t.tokenRegistry == nil {
+		return value.String()
+	}
+	return t.tokenRegistry.FormatAmount(contractAddress, value)
+}
+
+func (t *SupplyTracker) alert(contractAddress string, mismatches []string) {
+	if t.notifier == nil || len(mismatches) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ <b>Эмиссия токена не совпадает с totalSupply</b>\n<code>%s</code>\n\n%s",
+		contractAddress, strings.Join(mismatches, "\n"))
+
+	if err := t.notifier.SendAlert(message); err != nil {
+		logrus.Errorf("Ошибка отправки оповещения об эмиссии: %v", err)
+	}
+}
+
+// GetSupplyAtBlock возвращает эмиссию токена на блоке по проиндексированным событиям
+func (t *SupplyTracker) GetSupplyAtBlock(contractAddress string, block uint64) (*big.Int, error) {
+	snapshot, err := t.supplyRepo.GetSupplyAtBlock(contractAddress, block)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return big.NewInt(0), nil
+	}
+	return snapshot.TotalSupply.Int(), nil
+}
+
+// GetSupplyHistory возвращает историю эмиссии токена в диапазоне блоков
+func (t *SupplyTracker) GetSupplyHistory(contractAddress string, fromBlock, toBlock uint64) ([]models.TotalSupplySnapshot, error) {
+	return t.supplyRepo.GetSupplyHistory(contractAddress, fromBlock, toBlock)
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/contracts"
+	"backend/pkg/ethereum"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	tokenSupplyRefreshInterval = 5 * time.Minute
+	tokenFailureTTL            = 10 * time.Minute // сколько не повторять чтение метаданных после ошибки
+)
+
+// errTokenFetchBackoff - метаданные недавно не удалось прочитать, повторная попытка отложена
+var errTokenFetchBackoff = errors.New("чтение метаданных отложено после ошибки")
+
+// TokenRegistry хранит метаданные токенов (name, symbol, decimals, totalSupply) с кэшированием в памяти
+type TokenRegistry struct {
+	ethClient *ethereum.Client
+	tokenRepo *repositories.TokenRepository
+	erc20ABI  abi.ABI
+	cache     map[string]*models.Token
+	failures  map[string]time.Time // время последней ошибки чтения метаданных из сети
+	mu        sync.RWMutex
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewTokenRegistry(ethClient *ethereum.Client, tokenRepo *repositories.TokenRepository) (*TokenRegistry, error) {
+	parsed, err := contracts.LoadERC20ABI()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenRegistry{
+		ethClient: ethClient,
+		tokenRepo: tokenRepo,
+		erc20ABI:  parsed,
+		cache:     make(map[string]*models.Token),
+		failures:  make(map[string]time.Time),
+	}, nil
+}
+
+// Start запускает периодическое обновление totalSupply известных токенов
+func (r *TokenRegistry) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(tokenSupplyRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.RefreshTotalSupply(ctx); err != nil {
+					logrus.Errorf("Ошибка обновления total supply токенов: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *TokenRegistry) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+}
+
+// GetToken возвращает метаданные токена; при первой встрече контракта читает их из сети и сохраняет.
+// После ошибки чтения из сети повторная попытка откладывается на tokenFailureTTL (errTokenFetchBackoff)
+func (r *TokenRegistry) GetToken(ctx context.Context, contractAddress string) (*models.Token, error) {
+	if token := r.getCached(contractAddress); token != nil {
+		return token, nil
+	}
+	if r.recentlyFailed(contractAddress) {
+		return nil, fmt.Errorf("токен %s: %w", contractAddress, errTokenFetchBackoff)
+	}
+
+	token, err := r.tokenRepo.GetToken(contractAddress)
+	if err == nil {
+		r.setCached(token)
+		return token, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	// Контракт встречается впервые - читаем метаданные из сети
+	token, err = r.fetchMetadata(ctx, common.HexToAddress(contractAddress))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.setFailed(contractAddress)
+		}
+		return nil, err
+	}
+
+	if err := r.tokenRepo.SaveToken(token); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения токена %s: %v", contractAddress, err)
+	}
+
+	r.setCached(token)
+	logrus.Infof("Зарегистрирован токен %s: %s (%s)", contractAddress, token.Name, token.Symbol)
+	return token, nil
+}
+
+// FormatAmount форматирует количество токена с учетом decimals и символа.
+// Использует только уже известные метаданные, при их отсутствии возвращает сырое значение.
+func (r *TokenRegistry) FormatAmount(contractAddress string, value *big.Int) string {
+	token := r.getCached(contractAddress)
+	if token == nil {
+		loaded, err := r.tokenRepo.GetToken(contractAddress)
+		if err != nil {
+			if value == nil {
+				return "0"
+			}
+			return value.String()
+		}
+		r.setCached(loaded)
+		token = loaded
+	}
+	return token.FormatAmount(value)
+}
+
+// RefreshTotalSupply перечитывает totalSupply всех известных токенов
+func (r *TokenRegistry) RefreshTotalSupply(ctx context.Context) error {
+	tokens, err := r.tokenRepo.GetAllTokens()
+	if err != nil {
+		return err
+	}
+
+	for i := range tokens {
+		token := &tokens[i]
+		supply, err := r.callUint256(ctx, common.HexToAddress(token.ContractAddress), "totalSupply")
+		if err != nil {
+			logrus.Debugf("Не удалось получить total supply для %s: %v", token.ContractAddress, err)
+			continue
+		}
+
+		now := time.Now()
+		token.TotalSupply = models.NewBigInt(supply)
+		token.SupplyUpdatedAt = &now
+
+		if err := r.tokenRepo.UpdateTotalSupply(token.ContractAddress, token.TotalSupply, now); err != nil {
+			logrus.Errorf("Ошибка сохранения total supply для %s: %v", token.ContractAddress, err)
+			continue
+		}
+		r.setCached(token)
+	}
+
+	return nil
+}
+
+// fetchMetadata читает метаданные токена, допуская нестандартные реализации
+func (r *TokenRegistry) fetchMetadata(ctx context.Context, address common.Address) (*models.Token, error) {
+	code, err := r.ethClient.GetClient().CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения кода контракта %s: %v", address.Hex(), err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("по адресу %s нет контракта", address.Hex())
+	}
+
+	token := &models.Token{
+		ContractAddress: address.Hex(),
+		Name:            r.callString(ctx, address, "name"),
+		Symbol:          r.callString(ctx, address, "symbol"),
+	}
+
+	if decimals, err := r.callUint256(ctx, address, "decimals"); err == nil && decimals.IsUint64() && decimals.Uint64() <= 255 {
+		value := uint8(decimals.Uint64())
+		token.Decimals = &value
+	}
+
+	if supply, err := r.callUint256(ctx, address, "totalSupply"); err == nil {
+		now := time.Now()
+		token.TotalSupply = models.NewBigInt(supply)
+		token.SupplyUpdatedAt = &now
+	}
+
+	return token, nil
+}
+
+func (r *TokenRegistry) call(ctx context.Context, address common.Address, method string) ([]byte, error) {
+	input, err := r.erc20ABI.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ethClient.GetClient().CallContract(ctx, eth.CallMsg{To: &address, Data: input}, nil)
+}
+
+// callString читает строковое поле; поддерживает старые токены, возвращающие bytes32
+func (r *TokenRegistry) callString(ctx context.Context, address common.Address, method string) string {
+	data, err := r.call(ctx, address, method)
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+
+	var value string
+	if out, err := r.erc20ABI.Unpack(method, data); err == nil && len(out) > 0 {
+		value, _ = out[0].(string)
+	} else if len(data) == 32 {
+		value = strings.TrimRight(string(data), "\x00")
+	}
+
+	return strings.TrimSpace(strings.ToValidUTF8(strings.ReplaceAll(value, "\x00", ""), ""))
+}
+
+// callUint256 читает числовое поле из первого слова ответа
+func (r *TokenRegistry) callUint256(ctx context.Context, address common.Address, method string) (*big.Int, error) {
+	data, err := r.call(ctx, address, method)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 32 {
+		return nil, fmt.Errorf("пустой ответ %s()", method)
+	}
+	return new(big.Int).SetBytes(data[:32]), nil
+}
+
+func (r *TokenRegistry) getCached(contractAddress string) *models.Token {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cache[contractAddress]
+}
+
+func (r *TokenRegistry) setCached(token *models.Token) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache[token.ContractAddress] = token
+	delete(r.failures, token.ContractAddress)
+}
+
+func (r *TokenRegistry) recentlyFailed(contractAddress string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	failedAt, exists := r.failures[contractAddress]
+	return exists && time.Since(failedAt) < tokenFailureTTL
+}
+
+func (r *TokenRegistry) setFailed(contractAddress string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures[contractAddress] = time.Now()
+}
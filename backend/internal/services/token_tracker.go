@@ -12,7 +12,8 @@ import (
 )
 
 type TokenTracker struct {
-	accountRepo *repositories.AccountRepository
+	accountRepo   *repositories.AccountRepository
+	tokenRegistry *TokenRegistry
 }
 
 // ИСПРАВЛЕНО: добавлен конструктор с dependency injection
@@ -29,6 +30,19 @@ func NewTokenTrackerDeprecated() *TokenTracker {
 	}
 }
 
+// SetTokenRegistry подключает реестр токенов для форматирования сумм в логах
+func (t *TokenTracker) SetTokenRegistry(registry *TokenRegistry) {
+	t.tokenRegistry = registry
+}
+
+// formatAmount форматирует сумму токена, если реестр подключен
+func (t *TokenTracker) formatAmount(tokenAddress string, value *big.Int) string {
+	if t.tokenRegistry == nil {
+		return value.String()
+	}
+	return t.tokenRegistry.FormatAmount(tokenAddress, value)
+}
+
 // TrackERC20Transfer обрабатывает Transfer событие токена
 func (t *TokenTracker) TrackERC20Transfer(log types.Log) error {
 	// Проверяем, что это Transfer событие (должно быть 3 топика)
@@ -66,53 +80,47 @@ func (t *TokenTracker) TrackERC20Transfer(log types.Log) error {
 	}
 
 	logrus.Debugf("Обработан Transfer: от %s к %s токен %s значение %s",
-		from.Hex(), to.Hex(), log.Address.Hex(), value.String())
+		from.Hex(), to.Hex(), log.Address.Hex(), t.formatAmount(log.Address.Hex(), value))
 
 	return nil
 }
 
-// UpdateTokenBalance обновляет баланс токена у аккаунта
+// UpdateTokenBalance атомарно обновляет баланс токена у аккаунта
 func (t *TokenTracker) UpdateTokenBalance(address, tokenAddress string, amount *big.Int, isIncoming bool) error {
-	// Получаем текущий баланс
-	balance, err := t.accountRepo.GetTokenBalance(address, tokenAddress)
+	delta := new(big.Int).Set(amount)
+	if !isIncoming {
+		delta.Neg(delta)
+	}
+
+	// Защита от отрицательного баланса выполняется в SQL
+	newBalance, err := t.accountRepo.AdjustTokenBalance(address, tokenAddress, delta)
 	if err != nil {
 		return err
 	}
 
-	// Получаем текущий баланс как big.Int
-	currentBalance := balance.GetBalance()
-
-	// Обновляем баланс
-	if isIncoming {
-		currentBalance = currentBalance.Add(currentBalance, amount)
-	} else {
-		currentBalance = currentBalance.Sub(currentBalance, amount)
-		// Защита от отрицательного баланса
-		if currentBalance.Sign() < 0 {
-			logrus.Debugf("Предотвращение отрицательного баланса для %s токен %s: было бы %s, устанавливаем 0",
-				address, tokenAddress, currentBalance.String())
-			currentBalance = big.NewInt(0)
-		}
-	}
+	logrus.Debugf("Обновлен баланс %s токен %s: %s",
+		address, tokenAddress, t.formatAmount(tokenAddress, newBalance))
 
-	// Сохраняем обновленный баланс
-	balance.SetBalance(currentBalance)
+	return nil
+}
 
-	if err := t.accountRepo.UpdateTokenBalance(balance); err != nil {
-		return err
+// CalculateTokenVolume рассчитывает отправленный, полученный и чистый объем токена аккаунта за период [from, to)
+func (t *TokenTracker) CalculateTokenVolume(address, tokenAddress string, from, to time.Time) (*repositories.TokenVolume, error) {
+	volume, err := t.accountRepo.GetTokenVolume(address, tokenAddress, from, to)
+	if err != nil {
+		return nil, err
 	}
 
-	logrus.Debugf("Обновлен баланс %s токен %s: %s",
-		address, tokenAddress, currentBalance.String())
+	logrus.Debugf("Объем токена %s для %s с %v по %v: отправлено %s, получено %s",
+		tokenAddress, address, from, to,
+		t.formatAmount(tokenAddress, volume.Sent), t.formatAmount(tokenAddress, volume.Received))
 
-	return nil
+	return volume, nil
 }
 
-// CalculateTokenVolume рассчитывает объем токена с момента времени
-func (t *TokenTracker) CalculateTokenVolume(address, tokenAddress string, since time.Time) (*big.Int, error) {
-	// Это сложная операция, требующая анализа всех Transfer событий
-	// Пока возвращаем 0, можно реализовать позже
-	return big.NewInt(0), nil
+// CalculateTokenVolumes рассчитывает объемы аккаунта за период [from, to) по всем токенам
+func (t *TokenTracker) CalculateTokenVolumes(address string, from, to time.Time) ([]repositories.TokenVolume, error) {
+	return t.accountRepo.GetTokenVolumes(address, from, to)
 }
 
 // GetTokenBalance возвращает текущий баланс токена у аккаунта
@@ -122,7 +130,7 @@ func (t *TokenTracker) GetTokenBalance(address, tokenAddress string) (*big.Int,
 		return big.NewInt(0), err
 	}
 
-	return balance.GetBalance(), nil
+	return balance.Balance.Int(), nil
 }
 
 // GetAccountTokens возвращает все токены с ненулевым балансом у аккаунта
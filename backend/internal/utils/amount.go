@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	ETHDecimals = 18
+	ETHSymbol   = "ETH"
+
+	DefaultAmountPrecision = 6 // знаков после запятой по умолчанию
+)
+
+var amountPrecision atomic.Int32
+
+func init() {
+	amountPrecision.Store(DefaultAmountPrecision)
+}
+
+// SetAmountPrecision задает количество знаков после запятой для всех выводимых сумм.
+// Отрицательное значение - без округления (все знаки decimals)
+func SetAmountPrecision(precision int) {
+	amountPrecision.Store(int32(precision))
+}
+
+// AmountPrecision возвращает текущую точность вывода сумм
+func AmountPrecision() int {
+	return int(amountPrecision.Load())
+}
+
+// FormatUnits переводит сырое целое значение в единицы с decimals знаками, округляя до precision знаков
+// (половина - от нуля). Хвостовые нули дробной части отбрасываются
+func FormatUnits(value *big.Int, decimals uint8, precision int) string {
+	if value == nil {
+		return "0"
+	}
+	if decimals == 0 {
+		return value.String()
+	}
+	if precision < 0 || precision > int(decimals) {
+		precision = int(decimals)
+	}
+
+	abs := new(big.Int).Abs(value)
+	divisor := pow10(int(decimals))
+
+	// Округляем abs / 10^(decimals-precision)
+	step := pow10(int(decimals) - precision)
+	scaled, remainder := new(big.Int).QuoRem(abs, step, new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(step) >= 0 {
+		scaled.Add(scaled, big.NewInt(1))
+	}
+
+	unit := new(big.Int).Quo(divisor, step)
+	integer, fraction := new(big.Int).QuoRem(scaled, unit, new(big.Int))
+
+	result := integer.String()
+	if precision > 0 && fraction.Sign() > 0 {
+		digits := fraction.String()
+		digits = strings.Repeat("0", precision-len(digits)) + digits
+		result += "." + strings.TrimRight(digits, "0")
+	}
+
+	if value.Sign() < 0 && (integer.Sign() > 0 || fraction.Sign() > 0) {
+		result = "-" + result
+	}
+	return result
+}
+
+// FormatAmount форматирует сумму токена с decimals и символом с текущей точностью
+func FormatAmount(value *big.Int, decimals uint8, symbol string) string {
+	return JoinSymbol(FormatUnits(value, decimals, AmountPrecision()), symbol)
+}
+
+// FormatETH форматирует сумму в wei как ETH с текущей точностью
+func FormatETH(wei *big.Int) string {
+	return FormatAmount(wei, ETHDecimals, ETHSymbol)
+}
+
+// JoinSymbol добавляет символ к сумме, если он известен
+func JoinSymbol(amount, symbol string) string {
+	if symbol == "" {
+		return amount
+	}
+	return amount + " " + symbol
+}
+
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		decimals  uint8
+		precision int
+		want      string
+	}{
+		{"ноль", "0", 18, 6, "0"},
+		{"без decimals", "-12345", 0, 6, "-12345"},
+		{"целое", "2000000000000000000", 18, 6, "2"},
+		{"хвостовые нули отбрасываются", "1100000000000000000", 18, 6, "1.1"},
+		{"меньше половины - вниз", "1000000499999999999", 18, 6, "1"},
+		{"ровно половина - от нуля", "1000000500000000000", 18, 6, "1.000001"},
+		{"больше половины - вверх", "1000000500000000001", 18, 6, "1.000001"},
+		{"округление с переносом в целую часть", "999999500000000000", 18, 6, "1"},
+		{"отрицательная половина - от нуля", "-1000000500000000000", 18, 6, "-1.000001"},
+		{"отрицательное меньше половины", "-1000000499999999999", 18, 6, "-1"},
+		{"отрицательное округляется до нуля без знака", "-1", 18, 6, "0"},
+		{"положительное округляется до нуля", "499999999999", 18, 6, "0"},
+		{"нулевая точность, половина", "2500000000000000000", 18, 0, "3"},
+		{"нулевая точность, отрицательная половина", "-2500000000000000000", 18, 0, "-3"},
+		{"нулевая точность, меньше половины", "2499999999999999999", 18, 0, "2"},
+		{"точность больше decimals", "12345", 2, 10, "123.45"},
+		{"отрицательная точность - все знаки", "1", 18, -1, "0.000000000000000001"},
+		{"ведущие нули дробной части", "1050000", 6, 6, "1.05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := new(big.Int).SetString(tt.value, 10)
+			if !ok {
+				t.Fatalf("неверное значение %q", tt.value)
+			}
+			if got := FormatUnits(value, tt.decimals, tt.precision); got != tt.want {
+				t.Errorf("FormatUnits(%s, %d, %d) = %q, ожидалось %q", tt.value, tt.decimals, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatUnitsNil(t *testing.T) {
+	if got := FormatUnits(nil, 18, 6); got != "0" {
+		t.Errorf("FormatUnits(nil) = %q, ожидалось \"0\"", got)
+	}
+}
+
+func TestFormatUnitsKeepsValue(t *testing.T) {
+	value := big.NewInt(-1500)
+	FormatUnits(value, 3, 0)
+	if value.Cmp(big.NewInt(-1500)) != 0 {
+		t.Errorf("FormatUnits изменил аргумент: %s", value)
+	}
+}
@@ -1,10 +1,40 @@
 package utils
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
+)
 
-// GetCurrentPeriodStart возвращает начало текущего 15-секундного периода
+// DefaultActivityPeriod - базовый период агрегации активности по умолчанию
+const DefaultActivityPeriod = 15 * time.Second
+
+var activityPeriod atomic.Int64
+
+func init() {
+	activityPeriod.Store(int64(DefaultActivityPeriod))
+}
+
+// SetActivityPeriod задает базовый период агрегации активности.
+// Период должен делить минуту нацело, иначе из него нельзя собрать минутные срезы - тогда остается период по умолчанию
+func SetActivityPeriod(period time.Duration) bool {
+	if period <= 0 || time.Minute%period != 0 {
+		return false
+	}
+	activityPeriod.Store(int64(period))
+	return true
+}
+
+// ActivityPeriod возвращает базовый период агрегации активности
+func ActivityPeriod() time.Duration {
+	return time.Duration(activityPeriod.Load())
+}
+
+// PeriodStart возвращает начало базового периода, которому принадлежит момент времени
+func PeriodStart(t time.Time) time.Time {
+	return t.Truncate(ActivityPeriod())
+}
+
+// GetCurrentPeriodStart возвращает начало текущего базового периода
 func GetCurrentPeriodStart() time.Time {
-	now := time.Now()
-	return time.Date(now.Year(), now.Month(), now.Day(),
-		now.Hour(), now.Minute(), (now.Second()/15)*15, 0, now.Location())
+	return PeriodStart(time.Now())
 }
@@ -0,0 +1,78 @@
+package contracts
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+//go:generate abigen --abi abi/AnalyzerToken.abi --pkg contracts --type AnalyzerToken --out analyzer_token.go
+
+//go:embed abi/AnalyzerToken.abi
+var analyzerTokenABI []byte
+
+//go:embed abi/ERC20.abi
+var erc20ABI []byte
+
+var (
+	artifactPath string
+	artifactMux  sync.RWMutex
+)
+
+// SetArtifactPath задает путь к внешнему артефакту Hardhat, который используется вместо встроенного ABI.
+// Пустая строка возвращает встроенный ABI.
+func SetArtifactPath(path string) {
+	artifactMux.Lock()
+	defer artifactMux.Unlock()
+	artifactPath = path
+}
+
+// LoadAnalyzerTokenABI возвращает ABI контракта AnalyzerToken
+func LoadAnalyzerTokenABI() (abi.ABI, error) {
+	artifactMux.RLock()
+	path := artifactPath
+	artifactMux.RUnlock()
+
+	if path == "" {
+		return ParseABI(analyzerTokenABI)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("ошибка чтения артефакта %s: %v", path, err)
+	}
+	return ParseABI(data)
+}
+
+// LoadERC20ABI возвращает стандартный ABI ERC20
+func LoadERC20ABI() (abi.ABI, error) {
+	return ParseABI(erc20ABI)
+}
+
+// ParseABI разбирает ABI, заданный массивом или артефактом Hardhat
+func ParseABI(data []byte) (abi.ABI, error) {
+	data = bytes.TrimSpace(data)
+
+	// Артефакт Hardhat - объект с полем abi
+	if len(data) > 0 && data[0] == '{' {
+		var artifact ContractArtifact
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return abi.ABI{}, fmt.Errorf("ошибка чтения артефакта: %v", err)
+		}
+		if artifact.Abi == nil {
+			return abi.ABI{}, fmt.Errorf("в артефакте отсутствует ABI")
+		}
+		data = artifact.Abi
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("ошибка парсинга ABI: %v", err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,1929 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// AnalyzerTokenMetaData contains all meta data concerning the AnalyzerToken contract.
+var AnalyzerTokenMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"symbol\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"initialSupply\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"allowance\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"needed\",\"type\":\"uint256\"}],\"name\":\"ERC20InsufficientAllowance\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"balance\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"needed\",\"type\":\"uint256\"}],\"name\":\"ERC20InsufficientBalance\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"approver\",\"type\":\"address\"}],\"name\":\"ERC20InvalidApprover\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"receiver\",\"type\":\"address\"}],\"name\":\"ERC20InvalidReceiver\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"}],\"name\":\"ERC20InvalidSender\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"}],\"name\":\"ERC20InvalidSpender\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"OwnableInvalidOwner\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"OwnableUnauthorizedAccount\",\"type\":\"error\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\",\"indexed\":true},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\",\"indexed\":true},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\",\"indexed\":false},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\",\"indexed\":false},{\"internalType\":\"bytes4\",\"name\":\"methodId\",\"type\":\"bytes4\",\"indexed\":false},{\"internalType\":\"bool\",\"name\":\"success\",\"type\":\"bool\",\"indexed\":false},{\"internalType\":\"uint256\",\"name\":\"gasUsed\",\"type\":\"uint256\",\"indexed\":false}],\"name\":\"ExternalTransactionRecorded\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"previousOwner\",\"type\":\"address\",\"indexed\":true},{\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\",\"indexed\":true}],\"name\":\"OwnershipTransferred\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\",\"indexed\":true},{\"internalType\":\"uint256\",\"name\":\"totalTransactions\",\"type\":\"uint256\",\"indexed\":false},{\"internalType\":\"uint256\",\"name\":\"totalSent\",\"type\":\"uint256\",\"indexed\":false},{\"internalType\":\"uint256\",\"name\":\"totalReceived\",\"type\":\"uint256\",\"indexed\":false},{\"internalType\":\"uint256\",\"name\":\"externalTransactions\",\"type\":\"uint256\",\"indexed\":false}],\"name\":\"StatsUpdated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\",\"indexed\":true},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\",\"indexed\":true},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\",\"indexed\":false},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\",\"indexed\":false},{\"internalType\":\"enumAnalyzerToken.TransactionType\",\"name\":\"txType\",\"type\":\"uint8\",\"indexed\":false},{\"internalType\":\"bytes32\",\"name\":\"description\",\"type\":\"bytes32\",\"indexed\":false}],\"name\":\"TransactionRecorded\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"MAX_HISTORY_DAYS\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"MAX_TRANSACTIONS_PER_REQUEST\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"}],\"name\":\"getAccountStats\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"totalTx\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalSent\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalReceived\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lastActivity\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"firstActivity\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"blockNumber\",\"type\":\"uint256\"}],\"name\":\"getBalanceAtBlock\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"}],\"name\":\"getExtendedAccountStats\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"totalTx\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalSent\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalReceived\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lastActivity\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"firstActivity\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"externalTx\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"}],\"name\":\"getExternalTransactionCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"offset\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"limit\",\"type\":\"uint256\"}],\"name\":\"getExternalTransactions\",\"outputs\":[{\"internalType\":\"address[]\",\"name\":\"froms\",\"type\":\"address[]\"},{\"internalType\":\"address[]\",\"name\":\"tos\",\"type\":\"address[]\"},{\"internalType\":\"uint256[]\",\"name\":\"values\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"timestamps\",\"type\":\"uint256[]\"},{\"internalType\":\"bytes4[]\",\"name\":\"methodIds\",\"type\":\"bytes4[]\"},{\"internalType\":\"bool[]\",\"name\":\"successes\",\"type\":\"bool[]\"},{\"internalType\":\"uint256[]\",\"name\":\"gasUsed\",\"type\":\"uint256[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"}],\"name\":\"getTransactionCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"offset\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"limit\",\"type\":\"uint256\"}],\"name\":\"getTransactions\",\"outputs\":[{\"internalType\":\"address[]\",\"name\":\"froms\",\"type\":\"address[]\"},{\"internalType\":\"address[]\",\"name\":\"tos\",\"type\":\"address[]\"},{\"internalType\":\"uint256[]\",\"name\":\"amounts\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"timestamps\",\"type\":\"uint256[]\"},{\"internalType\":\"enumAnalyzerToken.TransactionType[]\",\"name\":\"types\",\"type\":\"uint8[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"descriptions\",\"type\":\"bytes32[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"fromTimestamp\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"toTimestamp\",\"type\":\"uint256\"}],\"name\":\"getVolumeForPeriod\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"sent\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"received\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"internalType\":\"bytes4\",\"name\":\"methodId\",\"type\":\"bytes4\"},{\"internalType\":\"bool\",\"name\":\"success\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"gasUsed\",\"type\":\"uint256\"}],\"name\":\"recordExternalTransaction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"renounceOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"transferOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"enumAnalyzerToken.TransactionType\",\"name\":\"txType\",\"type\":\"uint8\"},{\"internalType\":\"bytes32\",\"name\":\"description\",\"type\":\"bytes32\"}],\"name\":\"transferWithInfo\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+}
+
+// AnalyzerTokenABI is the input ABI used to generate the binding from.
+// Deprecated: Use AnalyzerTokenMetaData.ABI instead.
+var AnalyzerTokenABI = AnalyzerTokenMetaData.ABI
+
+// AnalyzerToken is an auto generated Go binding around an Ethereum contract.
+type AnalyzerToken struct {
+	AnalyzerTokenCaller     // Read-only binding to the contract
+	AnalyzerTokenTransactor // Write-only binding to the contract
+	AnalyzerTokenFilterer   // Log filterer for contract events
+}
+
+// AnalyzerTokenCaller is an auto generated read-only Go binding around an Ethereum contract.
+type AnalyzerTokenCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AnalyzerTokenTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type AnalyzerTokenTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AnalyzerTokenFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type AnalyzerTokenFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AnalyzerTokenSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type AnalyzerTokenSession struct {
+	Contract     *AnalyzerToken    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// AnalyzerTokenCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type AnalyzerTokenCallerSession struct {
+	Contract *AnalyzerTokenCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// AnalyzerTokenTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type AnalyzerTokenTransactorSession struct {
+	Contract     *AnalyzerTokenTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// AnalyzerTokenRaw is an auto generated low-level Go binding around an Ethereum contract.
+type AnalyzerTokenRaw struct {
+	Contract *AnalyzerToken // Generic contract binding to access the raw methods on
+}
+
+// AnalyzerTokenCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type AnalyzerTokenCallerRaw struct {
+	Contract *AnalyzerTokenCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// AnalyzerTokenTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type AnalyzerTokenTransactorRaw struct {
+	Contract *AnalyzerTokenTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewAnalyzerToken creates a new instance of AnalyzerToken, bound to a specific deployed contract.
+func NewAnalyzerToken(address common.Address, backend bind.ContractBackend) (*AnalyzerToken, error) {
+	contract, err := bindAnalyzerToken(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerToken{AnalyzerTokenCaller: AnalyzerTokenCaller{contract: contract}, AnalyzerTokenTransactor: AnalyzerTokenTransactor{contract: contract}, AnalyzerTokenFilterer: AnalyzerTokenFilterer{contract: contract}}, nil
+}
+
+// NewAnalyzerTokenCaller creates a new read-only instance of AnalyzerToken, bound to a specific deployed contract.
+func NewAnalyzerTokenCaller(address common.Address, caller bind.ContractCaller) (*AnalyzerTokenCaller, error) {
+	contract, err := bindAnalyzerToken(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenCaller{contract: contract}, nil
+}
+
+// NewAnalyzerTokenTransactor creates a new write-only instance of AnalyzerToken, bound to a specific deployed contract.
+func NewAnalyzerTokenTransactor(address common.Address, transactor bind.ContractTransactor) (*AnalyzerTokenTransactor, error) {
+	contract, err := bindAnalyzerToken(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenTransactor{contract: contract}, nil
+}
+
+// NewAnalyzerTokenFilterer creates a new log filterer instance of AnalyzerToken, bound to a specific deployed contract.
+func NewAnalyzerTokenFilterer(address common.Address, filterer bind.ContractFilterer) (*AnalyzerTokenFilterer, error) {
+	contract, err := bindAnalyzerToken(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenFilterer{contract: contract}, nil
+}
+
+// bindAnalyzerToken binds a generic wrapper to an already deployed contract.
+func bindAnalyzerToken(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := AnalyzerTokenMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AnalyzerToken *AnalyzerTokenRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AnalyzerToken.Contract.AnalyzerTokenCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AnalyzerToken *AnalyzerTokenRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.AnalyzerTokenTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AnalyzerToken *AnalyzerTokenRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.AnalyzerTokenTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AnalyzerToken *AnalyzerTokenCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AnalyzerToken.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AnalyzerToken *AnalyzerTokenTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AnalyzerToken *AnalyzerTokenTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.contract.Transact(opts, method, params...)
+}
+
+// MAXHISTORYDAYS is a free data retrieval call binding the contract method 0x746ed64e.
+//
+// Solidity: function MAX_HISTORY_DAYS() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) MAXHISTORYDAYS(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "MAX_HISTORY_DAYS")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MAXHISTORYDAYS is a free data retrieval call binding the contract method 0x746ed64e.
+//
+// Solidity: function MAX_HISTORY_DAYS() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) MAXHISTORYDAYS() (*big.Int, error) {
+	return _AnalyzerToken.Contract.MAXHISTORYDAYS(&_AnalyzerToken.CallOpts)
+}
+
+// MAXHISTORYDAYS is a free data retrieval call binding the contract method 0x746ed64e.
+//
+// Solidity: function MAX_HISTORY_DAYS() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) MAXHISTORYDAYS() (*big.Int, error) {
+	return _AnalyzerToken.Contract.MAXHISTORYDAYS(&_AnalyzerToken.CallOpts)
+}
+
+// MAXTRANSACTIONSPERREQUEST is a free data retrieval call binding the contract method 0x60f4a810.
+//
+// Solidity: function MAX_TRANSACTIONS_PER_REQUEST() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) MAXTRANSACTIONSPERREQUEST(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "MAX_TRANSACTIONS_PER_REQUEST")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MAXTRANSACTIONSPERREQUEST is a free data retrieval call binding the contract method 0x60f4a810.
+//
+// Solidity: function MAX_TRANSACTIONS_PER_REQUEST() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) MAXTRANSACTIONSPERREQUEST() (*big.Int, error) {
+	return _AnalyzerToken.Contract.MAXTRANSACTIONSPERREQUEST(&_AnalyzerToken.CallOpts)
+}
+
+// MAXTRANSACTIONSPERREQUEST is a free data retrieval call binding the contract method 0x60f4a810.
+//
+// Solidity: function MAX_TRANSACTIONS_PER_REQUEST() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) MAXTRANSACTIONSPERREQUEST() (*big.Int, error) {
+	return _AnalyzerToken.Contract.MAXTRANSACTIONSPERREQUEST(&_AnalyzerToken.CallOpts)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) Allowance(opts *bind.CallOpts, owner common.Address, spender common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "allowance", owner, spender)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.Allowance(&_AnalyzerToken.CallOpts, owner, spender)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.Allowance(&_AnalyzerToken.CallOpts, owner, spender)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "balanceOf", account)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) BalanceOf(account common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.BalanceOf(&_AnalyzerToken.CallOpts, account)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) BalanceOf(account common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.BalanceOf(&_AnalyzerToken.CallOpts, account)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_AnalyzerToken *AnalyzerTokenCaller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "decimals")
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_AnalyzerToken *AnalyzerTokenSession) Decimals() (uint8, error) {
+	return _AnalyzerToken.Contract.Decimals(&_AnalyzerToken.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) Decimals() (uint8, error) {
+	return _AnalyzerToken.Contract.Decimals(&_AnalyzerToken.CallOpts)
+}
+
+// GetAccountStats is a free data retrieval call binding the contract method 0x28cdfaeb.
+//
+// Solidity: function getAccountStats(address user) view returns(uint256 totalTx, uint256 totalSent, uint256 totalReceived, uint256 lastActivity, uint256 firstActivity)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetAccountStats(opts *bind.CallOpts, user common.Address) (struct {
+	TotalTx       *big.Int
+	TotalSent     *big.Int
+	TotalReceived *big.Int
+	LastActivity  *big.Int
+	FirstActivity *big.Int
+}, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getAccountStats", user)
+
+	outstruct := new(struct {
+		TotalTx       *big.Int
+		TotalSent     *big.Int
+		TotalReceived *big.Int
+		LastActivity  *big.Int
+		FirstActivity *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.TotalTx = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.TotalSent = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.TotalReceived = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.LastActivity = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.FirstActivity = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetAccountStats is a free data retrieval call binding the contract method 0x28cdfaeb.
+//
+// Solidity: function getAccountStats(address user) view returns(uint256 totalTx, uint256 totalSent, uint256 totalReceived, uint256 lastActivity, uint256 firstActivity)
+func (_AnalyzerToken *AnalyzerTokenSession) GetAccountStats(user common.Address) (struct {
+	TotalTx       *big.Int
+	TotalSent     *big.Int
+	TotalReceived *big.Int
+	LastActivity  *big.Int
+	FirstActivity *big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetAccountStats(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetAccountStats is a free data retrieval call binding the contract method 0x28cdfaeb.
+//
+// Solidity: function getAccountStats(address user) view returns(uint256 totalTx, uint256 totalSent, uint256 totalReceived, uint256 lastActivity, uint256 firstActivity)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetAccountStats(user common.Address) (struct {
+	TotalTx       *big.Int
+	TotalSent     *big.Int
+	TotalReceived *big.Int
+	LastActivity  *big.Int
+	FirstActivity *big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetAccountStats(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetBalanceAtBlock is a free data retrieval call binding the contract method 0xc6309256.
+//
+// Solidity: function getBalanceAtBlock(address user, uint256 blockNumber) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetBalanceAtBlock(opts *bind.CallOpts, user common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getBalanceAtBlock", user, blockNumber)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetBalanceAtBlock is a free data retrieval call binding the contract method 0xc6309256.
+//
+// Solidity: function getBalanceAtBlock(address user, uint256 blockNumber) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) GetBalanceAtBlock(user common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return _AnalyzerToken.Contract.GetBalanceAtBlock(&_AnalyzerToken.CallOpts, user, blockNumber)
+}
+
+// GetBalanceAtBlock is a free data retrieval call binding the contract method 0xc6309256.
+//
+// Solidity: function getBalanceAtBlock(address user, uint256 blockNumber) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetBalanceAtBlock(user common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return _AnalyzerToken.Contract.GetBalanceAtBlock(&_AnalyzerToken.CallOpts, user, blockNumber)
+}
+
+// GetExtendedAccountStats is a free data retrieval call binding the contract method 0x49eaafa3.
+//
+// Solidity: function getExtendedAccountStats(address user) view returns(uint256 totalTx, uint256 totalSent, uint256 totalReceived, uint256 lastActivity, uint256 firstActivity, uint256 externalTx)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetExtendedAccountStats(opts *bind.CallOpts, user common.Address) (struct {
+	TotalTx       *big.Int
+	TotalSent     *big.Int
+	TotalReceived *big.Int
+	LastActivity  *big.Int
+	FirstActivity *big.Int
+	ExternalTx    *big.Int
+}, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getExtendedAccountStats", user)
+
+	outstruct := new(struct {
+		TotalTx       *big.Int
+		TotalSent     *big.Int
+		TotalReceived *big.Int
+		LastActivity  *big.Int
+		FirstActivity *big.Int
+		ExternalTx    *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.TotalTx = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.TotalSent = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.TotalReceived = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.LastActivity = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.FirstActivity = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+	outstruct.ExternalTx = *abi.ConvertType(out[5], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetExtendedAccountStats is a free data retrieval call binding the contract method 0x49eaafa3.
+//
+// Solidity: function getExtendedAccountStats(address user) view returns(uint256 totalTx, uint256 totalSent, uint256 totalReceived, uint256 lastActivity, uint256 firstActivity, uint256 externalTx)
+func (_AnalyzerToken *AnalyzerTokenSession) GetExtendedAccountStats(user common.Address) (struct {
+	TotalTx       *big.Int
+	TotalSent     *big.Int
+	TotalReceived *big.Int
+	LastActivity  *big.Int
+	FirstActivity *big.Int
+	ExternalTx    *big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetExtendedAccountStats(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetExtendedAccountStats is a free data retrieval call binding the contract method 0x49eaafa3.
+//
+// Solidity: function getExtendedAccountStats(address user) view returns(uint256 totalTx, uint256 totalSent, uint256 totalReceived, uint256 lastActivity, uint256 firstActivity, uint256 externalTx)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetExtendedAccountStats(user common.Address) (struct {
+	TotalTx       *big.Int
+	TotalSent     *big.Int
+	TotalReceived *big.Int
+	LastActivity  *big.Int
+	FirstActivity *big.Int
+	ExternalTx    *big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetExtendedAccountStats(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetExternalTransactionCount is a free data retrieval call binding the contract method 0x7e15f50a.
+//
+// Solidity: function getExternalTransactionCount(address user) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetExternalTransactionCount(opts *bind.CallOpts, user common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getExternalTransactionCount", user)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetExternalTransactionCount is a free data retrieval call binding the contract method 0x7e15f50a.
+//
+// Solidity: function getExternalTransactionCount(address user) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) GetExternalTransactionCount(user common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.GetExternalTransactionCount(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetExternalTransactionCount is a free data retrieval call binding the contract method 0x7e15f50a.
+//
+// Solidity: function getExternalTransactionCount(address user) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetExternalTransactionCount(user common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.GetExternalTransactionCount(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetExternalTransactions is a free data retrieval call binding the contract method 0x9ce2d08e.
+//
+// Solidity: function getExternalTransactions(address user, uint256 offset, uint256 limit) view returns(address[] froms, address[] tos, uint256[] values, uint256[] timestamps, bytes4[] methodIds, bool[] successes, uint256[] gasUsed)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetExternalTransactions(opts *bind.CallOpts, user common.Address, offset *big.Int, limit *big.Int) (struct {
+	Froms      []common.Address
+	Tos        []common.Address
+	Values     []*big.Int
+	Timestamps []*big.Int
+	MethodIds  [][4]byte
+	Successes  []bool
+	GasUsed    []*big.Int
+}, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getExternalTransactions", user, offset, limit)
+
+	outstruct := new(struct {
+		Froms      []common.Address
+		Tos        []common.Address
+		Values     []*big.Int
+		Timestamps []*big.Int
+		MethodIds  [][4]byte
+		Successes  []bool
+		GasUsed    []*big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Froms = *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+	outstruct.Tos = *abi.ConvertType(out[1], new([]common.Address)).(*[]common.Address)
+	outstruct.Values = *abi.ConvertType(out[2], new([]*big.Int)).(*[]*big.Int)
+	outstruct.Timestamps = *abi.ConvertType(out[3], new([]*big.Int)).(*[]*big.Int)
+	outstruct.MethodIds = *abi.ConvertType(out[4], new([][4]byte)).(*[][4]byte)
+	outstruct.Successes = *abi.ConvertType(out[5], new([]bool)).(*[]bool)
+	outstruct.GasUsed = *abi.ConvertType(out[6], new([]*big.Int)).(*[]*big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetExternalTransactions is a free data retrieval call binding the contract method 0x9ce2d08e.
+//
+// Solidity: function getExternalTransactions(address user, uint256 offset, uint256 limit) view returns(address[] froms, address[] tos, uint256[] values, uint256[] timestamps, bytes4[] methodIds, bool[] successes, uint256[] gasUsed)
+func (_AnalyzerToken *AnalyzerTokenSession) GetExternalTransactions(user common.Address, offset *big.Int, limit *big.Int) (struct {
+	Froms      []common.Address
+	Tos        []common.Address
+	Values     []*big.Int
+	Timestamps []*big.Int
+	MethodIds  [][4]byte
+	Successes  []bool
+	GasUsed    []*big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetExternalTransactions(&_AnalyzerToken.CallOpts, user, offset, limit)
+}
+
+// GetExternalTransactions is a free data retrieval call binding the contract method 0x9ce2d08e.
+//
+// Solidity: function getExternalTransactions(address user, uint256 offset, uint256 limit) view returns(address[] froms, address[] tos, uint256[] values, uint256[] timestamps, bytes4[] methodIds, bool[] successes, uint256[] gasUsed)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetExternalTransactions(user common.Address, offset *big.Int, limit *big.Int) (struct {
+	Froms      []common.Address
+	Tos        []common.Address
+	Values     []*big.Int
+	Timestamps []*big.Int
+	MethodIds  [][4]byte
+	Successes  []bool
+	GasUsed    []*big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetExternalTransactions(&_AnalyzerToken.CallOpts, user, offset, limit)
+}
+
+// GetTransactionCount is a free data retrieval call binding the contract method 0x23ca0cd2.
+//
+// Solidity: function getTransactionCount(address user) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetTransactionCount(opts *bind.CallOpts, user common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getTransactionCount", user)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetTransactionCount is a free data retrieval call binding the contract method 0x23ca0cd2.
+//
+// Solidity: function getTransactionCount(address user) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) GetTransactionCount(user common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.GetTransactionCount(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetTransactionCount is a free data retrieval call binding the contract method 0x23ca0cd2.
+//
+// Solidity: function getTransactionCount(address user) view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetTransactionCount(user common.Address) (*big.Int, error) {
+	return _AnalyzerToken.Contract.GetTransactionCount(&_AnalyzerToken.CallOpts, user)
+}
+
+// GetTransactions is a free data retrieval call binding the contract method 0x7435f421.
+//
+// Solidity: function getTransactions(address user, uint256 offset, uint256 limit) view returns(address[] froms, address[] tos, uint256[] amounts, uint256[] timestamps, uint8[] types, bytes32[] descriptions)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetTransactions(opts *bind.CallOpts, user common.Address, offset *big.Int, limit *big.Int) (struct {
+	Froms        []common.Address
+	Tos          []common.Address
+	Amounts      []*big.Int
+	Timestamps   []*big.Int
+	Types        []uint8
+	Descriptions [][32]byte
+}, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getTransactions", user, offset, limit)
+
+	outstruct := new(struct {
+		Froms        []common.Address
+		Tos          []common.Address
+		Amounts      []*big.Int
+		Timestamps   []*big.Int
+		Types        []uint8
+		Descriptions [][32]byte
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Froms = *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+	outstruct.Tos = *abi.ConvertType(out[1], new([]common.Address)).(*[]common.Address)
+	outstruct.Amounts = *abi.ConvertType(out[2], new([]*big.Int)).(*[]*big.Int)
+	outstruct.Timestamps = *abi.ConvertType(out[3], new([]*big.Int)).(*[]*big.Int)
+	outstruct.Types = *abi.ConvertType(out[4], new([]uint8)).(*[]uint8)
+	outstruct.Descriptions = *abi.ConvertType(out[5], new([][32]byte)).(*[][32]byte)
+
+	return *outstruct, err
+
+}
+
+// GetTransactions is a free data retrieval call binding the contract method 0x7435f421.
+//
+// Solidity: function getTransactions(address user, uint256 offset, uint256 limit) view returns(address[] froms, address[] tos, uint256[] amounts, uint256[] timestamps, uint8[] types, bytes32[] descriptions)
+func (_AnalyzerToken *AnalyzerTokenSession) GetTransactions(user common.Address, offset *big.Int, limit *big.Int) (struct {
+	Froms        []common.Address
+	Tos          []common.Address
+	Amounts      []*big.Int
+	Timestamps   []*big.Int
+	Types        []uint8
+	Descriptions [][32]byte
+}, error) {
+	return _AnalyzerToken.Contract.GetTransactions(&_AnalyzerToken.CallOpts, user, offset, limit)
+}
+
+// GetTransactions is a free data retrieval call binding the contract method 0x7435f421.
+//
+// Solidity: function getTransactions(address user, uint256 offset, uint256 limit) view returns(address[] froms, address[] tos, uint256[] amounts, uint256[] timestamps, uint8[] types, bytes32[] descriptions)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetTransactions(user common.Address, offset *big.Int, limit *big.Int) (struct {
+	Froms        []common.Address
+	Tos          []common.Address
+	Amounts      []*big.Int
+	Timestamps   []*big.Int
+	Types        []uint8
+	Descriptions [][32]byte
+}, error) {
+	return _AnalyzerToken.Contract.GetTransactions(&_AnalyzerToken.CallOpts, user, offset, limit)
+}
+
+// GetVolumeForPeriod is a free data retrieval call binding the contract method 0x328082ff.
+//
+// Solidity: function getVolumeForPeriod(address user, uint256 fromTimestamp, uint256 toTimestamp) view returns(uint256 sent, uint256 received)
+func (_AnalyzerToken *AnalyzerTokenCaller) GetVolumeForPeriod(opts *bind.CallOpts, user common.Address, fromTimestamp *big.Int, toTimestamp *big.Int) (struct {
+	Sent     *big.Int
+	Received *big.Int
+}, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "getVolumeForPeriod", user, fromTimestamp, toTimestamp)
+
+	outstruct := new(struct {
+		Sent     *big.Int
+		Received *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Sent = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.Received = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetVolumeForPeriod is a free data retrieval call binding the contract method 0x328082ff.
+//
+// Solidity: function getVolumeForPeriod(address user, uint256 fromTimestamp, uint256 toTimestamp) view returns(uint256 sent, uint256 received)
+func (_AnalyzerToken *AnalyzerTokenSession) GetVolumeForPeriod(user common.Address, fromTimestamp *big.Int, toTimestamp *big.Int) (struct {
+	Sent     *big.Int
+	Received *big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetVolumeForPeriod(&_AnalyzerToken.CallOpts, user, fromTimestamp, toTimestamp)
+}
+
+// GetVolumeForPeriod is a free data retrieval call binding the contract method 0x328082ff.
+//
+// Solidity: function getVolumeForPeriod(address user, uint256 fromTimestamp, uint256 toTimestamp) view returns(uint256 sent, uint256 received)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) GetVolumeForPeriod(user common.Address, fromTimestamp *big.Int, toTimestamp *big.Int) (struct {
+	Sent     *big.Int
+	Received *big.Int
+}, error) {
+	return _AnalyzerToken.Contract.GetVolumeForPeriod(&_AnalyzerToken.CallOpts, user, fromTimestamp, toTimestamp)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_AnalyzerToken *AnalyzerTokenCaller) Name(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "name")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_AnalyzerToken *AnalyzerTokenSession) Name() (string, error) {
+	return _AnalyzerToken.Contract.Name(&_AnalyzerToken.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) Name() (string, error) {
+	return _AnalyzerToken.Contract.Name(&_AnalyzerToken.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AnalyzerToken *AnalyzerTokenCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AnalyzerToken *AnalyzerTokenSession) Owner() (common.Address, error) {
+	return _AnalyzerToken.Contract.Owner(&_AnalyzerToken.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) Owner() (common.Address, error) {
+	return _AnalyzerToken.Contract.Owner(&_AnalyzerToken.CallOpts)
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() view returns(string)
+func (_AnalyzerToken *AnalyzerTokenCaller) Symbol(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "symbol")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() view returns(string)
+func (_AnalyzerToken *AnalyzerTokenSession) Symbol() (string, error) {
+	return _AnalyzerToken.Contract.Symbol(&_AnalyzerToken.CallOpts)
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() view returns(string)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) Symbol() (string, error) {
+	return _AnalyzerToken.Contract.Symbol(&_AnalyzerToken.CallOpts)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCaller) TotalSupply(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _AnalyzerToken.contract.Call(opts, &out, "totalSupply")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenSession) TotalSupply() (*big.Int, error) {
+	return _AnalyzerToken.Contract.TotalSupply(&_AnalyzerToken.CallOpts)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() view returns(uint256)
+func (_AnalyzerToken *AnalyzerTokenCallerSession) TotalSupply() (*big.Int, error) {
+	return _AnalyzerToken.Contract.TotalSupply(&_AnalyzerToken.CallOpts)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactor) Approve(opts *bind.TransactOpts, spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.contract.Transact(opts, "approve", spender, value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenSession) Approve(spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.Approve(&_AnalyzerToken.TransactOpts, spender, value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactorSession) Approve(spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.Approve(&_AnalyzerToken.TransactOpts, spender, value)
+}
+
+// RecordExternalTransaction is a paid mutator transaction binding the contract method 0xf0b576a1.
+//
+// Solidity: function recordExternalTransaction(address from, address to, uint256 value, bytes4 methodId, bool success, uint256 gasUsed) returns()
+func (_AnalyzerToken *AnalyzerTokenTransactor) RecordExternalTransaction(opts *bind.TransactOpts, from common.Address, to common.Address, value *big.Int, methodId [4]byte, success bool, gasUsed *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.contract.Transact(opts, "recordExternalTransaction", from, to, value, methodId, success, gasUsed)
+}
+
+// RecordExternalTransaction is a paid mutator transaction binding the contract method 0xf0b576a1.
+//
+// Solidity: function recordExternalTransaction(address from, address to, uint256 value, bytes4 methodId, bool success, uint256 gasUsed) returns()
+func (_AnalyzerToken *AnalyzerTokenSession) RecordExternalTransaction(from common.Address, to common.Address, value *big.Int, methodId [4]byte, success bool, gasUsed *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.RecordExternalTransaction(&_AnalyzerToken.TransactOpts, from, to, value, methodId, success, gasUsed)
+}
+
+// RecordExternalTransaction is a paid mutator transaction binding the contract method 0xf0b576a1.
+//
+// Solidity: function recordExternalTransaction(address from, address to, uint256 value, bytes4 methodId, bool success, uint256 gasUsed) returns()
+func (_AnalyzerToken *AnalyzerTokenTransactorSession) RecordExternalTransaction(from common.Address, to common.Address, value *big.Int, methodId [4]byte, success bool, gasUsed *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.RecordExternalTransaction(&_AnalyzerToken.TransactOpts, from, to, value, methodId, success, gasUsed)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AnalyzerToken *AnalyzerTokenTransactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AnalyzerToken.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AnalyzerToken *AnalyzerTokenSession) RenounceOwnership() (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.RenounceOwnership(&_AnalyzerToken.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AnalyzerToken *AnalyzerTokenTransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.RenounceOwnership(&_AnalyzerToken.TransactOpts)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactor) Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.contract.Transact(opts, "transfer", to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.Transfer(&_AnalyzerToken.TransactOpts, to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactorSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.Transfer(&_AnalyzerToken.TransactOpts, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactor) TransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.contract.Transact(opts, "transferFrom", from, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenSession) TransferFrom(from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.TransferFrom(&_AnalyzerToken.TransactOpts, from, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactorSession) TransferFrom(from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.TransferFrom(&_AnalyzerToken.TransactOpts, from, to, value)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AnalyzerToken *AnalyzerTokenTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _AnalyzerToken.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AnalyzerToken *AnalyzerTokenSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.TransferOwnership(&_AnalyzerToken.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AnalyzerToken *AnalyzerTokenTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.TransferOwnership(&_AnalyzerToken.TransactOpts, newOwner)
+}
+
+// TransferWithInfo is a paid mutator transaction binding the contract method 0x3cb68852.
+//
+// Solidity: function transferWithInfo(address to, uint256 amount, uint8 txType, bytes32 description) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactor) TransferWithInfo(opts *bind.TransactOpts, to common.Address, amount *big.Int, txType uint8, description [32]byte) (*types.Transaction, error) {
+	return _AnalyzerToken.contract.Transact(opts, "transferWithInfo", to, amount, txType, description)
+}
+
+// TransferWithInfo is a paid mutator transaction binding the contract method 0x3cb68852.
+//
+// Solidity: function transferWithInfo(address to, uint256 amount, uint8 txType, bytes32 description) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenSession) TransferWithInfo(to common.Address, amount *big.Int, txType uint8, description [32]byte) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.TransferWithInfo(&_AnalyzerToken.TransactOpts, to, amount, txType, description)
+}
+
+// TransferWithInfo is a paid mutator transaction binding the contract method 0x3cb68852.
+//
+// Solidity: function transferWithInfo(address to, uint256 amount, uint8 txType, bytes32 description) returns(bool)
+func (_AnalyzerToken *AnalyzerTokenTransactorSession) TransferWithInfo(to common.Address, amount *big.Int, txType uint8, description [32]byte) (*types.Transaction, error) {
+	return _AnalyzerToken.Contract.TransferWithInfo(&_AnalyzerToken.TransactOpts, to, amount, txType, description)
+}
+
+// AnalyzerTokenApprovalIterator is returned from FilterApproval and is used to iterate over the raw logs and unpacked data for Approval events raised by the AnalyzerToken contract.
+type AnalyzerTokenApprovalIterator struct {
+	Event *AnalyzerTokenApproval // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AnalyzerTokenApprovalIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AnalyzerTokenApproval)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AnalyzerTokenApproval)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AnalyzerTokenApprovalIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AnalyzerTokenApprovalIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AnalyzerTokenApproval represents a Approval event raised by the AnalyzerToken contract.
+type AnalyzerTokenApproval struct {
+	Owner   common.Address
+	Spender common.Address
+	Value   *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterApproval is a free log retrieval operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: event Approval(address indexed owner, address indexed spender, uint256 value)
+func (_AnalyzerToken *AnalyzerTokenFilterer) FilterApproval(opts *bind.FilterOpts, owner []common.Address, spender []common.Address) (*AnalyzerTokenApprovalIterator, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var spenderRule []interface{}
+	for _, spenderItem := range spender {
+		spenderRule = append(spenderRule, spenderItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.FilterLogs(opts, "Approval", ownerRule, spenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenApprovalIterator{contract: _AnalyzerToken.contract, event: "Approval", logs: logs, sub: sub}, nil
+}
+
+// WatchApproval is a free log subscription operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: event Approval(address indexed owner, address indexed spender, uint256 value)
+func (_AnalyzerToken *AnalyzerTokenFilterer) WatchApproval(opts *bind.WatchOpts, sink chan<- *AnalyzerTokenApproval, owner []common.Address, spender []common.Address) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var spenderRule []interface{}
+	for _, spenderItem := range spender {
+		spenderRule = append(spenderRule, spenderItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.WatchLogs(opts, "Approval", ownerRule, spenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AnalyzerTokenApproval)
+				if err := _AnalyzerToken.contract.UnpackLog(event, "Approval", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseApproval is a log parse operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: event Approval(address indexed owner, address indexed spender, uint256 value)
+func (_AnalyzerToken *AnalyzerTokenFilterer) ParseApproval(log types.Log) (*AnalyzerTokenApproval, error) {
+	event := new(AnalyzerTokenApproval)
+	if err := _AnalyzerToken.contract.UnpackLog(event, "Approval", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AnalyzerTokenExternalTransactionRecordedIterator is returned from FilterExternalTransactionRecorded and is used to iterate over the raw logs and unpacked data for ExternalTransactionRecorded events raised by the AnalyzerToken contract.
+type AnalyzerTokenExternalTransactionRecordedIterator struct {
+	Event *AnalyzerTokenExternalTransactionRecorded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AnalyzerTokenExternalTransactionRecordedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AnalyzerTokenExternalTransactionRecorded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AnalyzerTokenExternalTransactionRecorded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AnalyzerTokenExternalTransactionRecordedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AnalyzerTokenExternalTransactionRecordedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AnalyzerTokenExternalTransactionRecorded represents a ExternalTransactionRecorded event raised by the AnalyzerToken contract.
+type AnalyzerTokenExternalTransactionRecorded struct {
+	From      common.Address
+	To        common.Address
+	Value     *big.Int
+	Timestamp *big.Int
+	MethodId  [4]byte
+	Success   bool
+	GasUsed   *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterExternalTransactionRecorded is a free log retrieval operation binding the contract event 0x1498df6181486b15181255badeeabfd9b9936c4706a5cea97275ac1d6fae7c60.
+//
+// Solidity: event ExternalTransactionRecorded(address indexed from, address indexed to, uint256 value, uint256 timestamp, bytes4 methodId, bool success, uint256 gasUsed)
+func (_AnalyzerToken *AnalyzerTokenFilterer) FilterExternalTransactionRecorded(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*AnalyzerTokenExternalTransactionRecordedIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.FilterLogs(opts, "ExternalTransactionRecorded", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenExternalTransactionRecordedIterator{contract: _AnalyzerToken.contract, event: "ExternalTransactionRecorded", logs: logs, sub: sub}, nil
+}
+
+// WatchExternalTransactionRecorded is a free log subscription operation binding the contract event 0x1498df6181486b15181255badeeabfd9b9936c4706a5cea97275ac1d6fae7c60.
+//
+// Solidity: event ExternalTransactionRecorded(address indexed from, address indexed to, uint256 value, uint256 timestamp, bytes4 methodId, bool success, uint256 gasUsed)
+func (_AnalyzerToken *AnalyzerTokenFilterer) WatchExternalTransactionRecorded(opts *bind.WatchOpts, sink chan<- *AnalyzerTokenExternalTransactionRecorded, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.WatchLogs(opts, "ExternalTransactionRecorded", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AnalyzerTokenExternalTransactionRecorded)
+				if err := _AnalyzerToken.contract.UnpackLog(event, "ExternalTransactionRecorded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseExternalTransactionRecorded is a log parse operation binding the contract event 0x1498df6181486b15181255badeeabfd9b9936c4706a5cea97275ac1d6fae7c60.
+//
+// Solidity: event ExternalTransactionRecorded(address indexed from, address indexed to, uint256 value, uint256 timestamp, bytes4 methodId, bool success, uint256 gasUsed)
+func (_AnalyzerToken *AnalyzerTokenFilterer) ParseExternalTransactionRecorded(log types.Log) (*AnalyzerTokenExternalTransactionRecorded, error) {
+	event := new(AnalyzerTokenExternalTransactionRecorded)
+	if err := _AnalyzerToken.contract.UnpackLog(event, "ExternalTransactionRecorded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AnalyzerTokenOwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the AnalyzerToken contract.
+type AnalyzerTokenOwnershipTransferredIterator struct {
+	Event *AnalyzerTokenOwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AnalyzerTokenOwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AnalyzerTokenOwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AnalyzerTokenOwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AnalyzerTokenOwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AnalyzerTokenOwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AnalyzerTokenOwnershipTransferred represents a OwnershipTransferred event raised by the AnalyzerToken contract.
+type AnalyzerTokenOwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AnalyzerToken *AnalyzerTokenFilterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*AnalyzerTokenOwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenOwnershipTransferredIterator{contract: _AnalyzerToken.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AnalyzerToken *AnalyzerTokenFilterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *AnalyzerTokenOwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AnalyzerTokenOwnershipTransferred)
+				if err := _AnalyzerToken.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AnalyzerToken *AnalyzerTokenFilterer) ParseOwnershipTransferred(log types.Log) (*AnalyzerTokenOwnershipTransferred, error) {
+	event := new(AnalyzerTokenOwnershipTransferred)
+	if err := _AnalyzerToken.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AnalyzerTokenStatsUpdatedIterator is returned from FilterStatsUpdated and is used to iterate over the raw logs and unpacked data for StatsUpdated events raised by the AnalyzerToken contract.
+type AnalyzerTokenStatsUpdatedIterator struct {
+	Event *AnalyzerTokenStatsUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AnalyzerTokenStatsUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AnalyzerTokenStatsUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AnalyzerTokenStatsUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AnalyzerTokenStatsUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AnalyzerTokenStatsUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AnalyzerTokenStatsUpdated represents a StatsUpdated event raised by the AnalyzerToken contract.
+type AnalyzerTokenStatsUpdated struct {
+	User                 common.Address
+	TotalTransactions    *big.Int
+	TotalSent            *big.Int
+	TotalReceived        *big.Int
+	ExternalTransactions *big.Int
+	Raw                  types.Log // Blockchain specific contextual infos
+}
+
+// FilterStatsUpdated is a free log retrieval operation binding the contract event 0xb4dc03f150e6d614b4a64b5aa6600a4f413726a20001138df59ecc01bb8f7983.
+//
+// Solidity: event StatsUpdated(address indexed user, uint256 totalTransactions, uint256 totalSent, uint256 totalReceived, uint256 externalTransactions)
+func (_AnalyzerToken *AnalyzerTokenFilterer) FilterStatsUpdated(opts *bind.FilterOpts, user []common.Address) (*AnalyzerTokenStatsUpdatedIterator, error) {
+
+	var userRule []interface{}
+	for _, userItem := range user {
+		userRule = append(userRule, userItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.FilterLogs(opts, "StatsUpdated", userRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenStatsUpdatedIterator{contract: _AnalyzerToken.contract, event: "StatsUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchStatsUpdated is a free log subscription operation binding the contract event 0xb4dc03f150e6d614b4a64b5aa6600a4f413726a20001138df59ecc01bb8f7983.
+//
+// Solidity: event StatsUpdated(address indexed user, uint256 totalTransactions, uint256 totalSent, uint256 totalReceived, uint256 externalTransactions)
+func (_AnalyzerToken *AnalyzerTokenFilterer) WatchStatsUpdated(opts *bind.WatchOpts, sink chan<- *AnalyzerTokenStatsUpdated, user []common.Address) (event.Subscription, error) {
+
+	var userRule []interface{}
+	for _, userItem := range user {
+		userRule = append(userRule, userItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.WatchLogs(opts, "StatsUpdated", userRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AnalyzerTokenStatsUpdated)
+				if err := _AnalyzerToken.contract.UnpackLog(event, "StatsUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseStatsUpdated is a log parse operation binding the contract event 0xb4dc03f150e6d614b4a64b5aa6600a4f413726a20001138df59ecc01bb8f7983.
+//
+// Solidity: event StatsUpdated(address indexed user, uint256 totalTransactions, uint256 totalSent, uint256 totalReceived, uint256 externalTransactions)
+func (_AnalyzerToken *AnalyzerTokenFilterer) ParseStatsUpdated(log types.Log) (*AnalyzerTokenStatsUpdated, error) {
+	event := new(AnalyzerTokenStatsUpdated)
+	if err := _AnalyzerToken.contract.UnpackLog(event, "StatsUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AnalyzerTokenTransactionRecordedIterator is returned from FilterTransactionRecorded and is used to iterate over the raw logs and unpacked data for TransactionRecorded events raised by the AnalyzerToken contract.
+type AnalyzerTokenTransactionRecordedIterator struct {
+	Event *AnalyzerTokenTransactionRecorded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AnalyzerTokenTransactionRecordedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AnalyzerTokenTransactionRecorded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AnalyzerTokenTransactionRecorded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AnalyzerTokenTransactionRecordedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AnalyzerTokenTransactionRecordedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AnalyzerTokenTransactionRecorded represents a TransactionRecorded event raised by the AnalyzerToken contract.
+type AnalyzerTokenTransactionRecorded struct {
+	From        common.Address
+	To          common.Address
+	Amount      *big.Int
+	Timestamp   *big.Int
+	TxType      uint8
+	Description [32]byte
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterTransactionRecorded is a free log retrieval operation binding the contract event 0xbeb9cd1e34ea59c6ad719f99d2555bd16038535e11c4bdf3be3ddc7e5cdbccdb.
+//
+// Solidity: event TransactionRecorded(address indexed from, address indexed to, uint256 amount, uint256 timestamp, uint8 txType, bytes32 description)
+func (_AnalyzerToken *AnalyzerTokenFilterer) FilterTransactionRecorded(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*AnalyzerTokenTransactionRecordedIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.FilterLogs(opts, "TransactionRecorded", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenTransactionRecordedIterator{contract: _AnalyzerToken.contract, event: "TransactionRecorded", logs: logs, sub: sub}, nil
+}
+
+// WatchTransactionRecorded is a free log subscription operation binding the contract event 0xbeb9cd1e34ea59c6ad719f99d2555bd16038535e11c4bdf3be3ddc7e5cdbccdb.
+//
+// Solidity: event TransactionRecorded(address indexed from, address indexed to, uint256 amount, uint256 timestamp, uint8 txType, bytes32 description)
+func (_AnalyzerToken *AnalyzerTokenFilterer) WatchTransactionRecorded(opts *bind.WatchOpts, sink chan<- *AnalyzerTokenTransactionRecorded, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.WatchLogs(opts, "TransactionRecorded", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AnalyzerTokenTransactionRecorded)
+				if err := _AnalyzerToken.contract.UnpackLog(event, "TransactionRecorded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTransactionRecorded is a log parse operation binding the contract event 0xbeb9cd1e34ea59c6ad719f99d2555bd16038535e11c4bdf3be3ddc7e5cdbccdb.
+//
+// Solidity: event TransactionRecorded(address indexed from, address indexed to, uint256 amount, uint256 timestamp, uint8 txType, bytes32 description)
+func (_AnalyzerToken *AnalyzerTokenFilterer) ParseTransactionRecorded(log types.Log) (*AnalyzerTokenTransactionRecorded, error) {
+	event := new(AnalyzerTokenTransactionRecorded)
+	if err := _AnalyzerToken.contract.UnpackLog(event, "TransactionRecorded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AnalyzerTokenTransferIterator is returned from FilterTransfer and is used to iterate over the raw logs and unpacked data for Transfer events raised by the AnalyzerToken contract.
+type AnalyzerTokenTransferIterator struct {
+	Event *AnalyzerTokenTransfer // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AnalyzerTokenTransferIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AnalyzerTokenTransfer)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AnalyzerTokenTransfer)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AnalyzerTokenTransferIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AnalyzerTokenTransferIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AnalyzerTokenTransfer represents a Transfer event raised by the AnalyzerToken contract.
+type AnalyzerTokenTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterTransfer is a free log retrieval operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_AnalyzerToken *AnalyzerTokenFilterer) FilterTransfer(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*AnalyzerTokenTransferIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.FilterLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzerTokenTransferIterator{contract: _AnalyzerToken.contract, event: "Transfer", logs: logs, sub: sub}, nil
+}
+
+// WatchTransfer is a free log subscription operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_AnalyzerToken *AnalyzerTokenFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *AnalyzerTokenTransfer, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _AnalyzerToken.contract.WatchLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AnalyzerTokenTransfer)
+				if err := _AnalyzerToken.contract.UnpackLog(event, "Transfer", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTransfer is a log parse operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_AnalyzerToken *AnalyzerTokenFilterer) ParseTransfer(log types.Log) (*AnalyzerTokenTransfer, error) {
+	event := new(AnalyzerTokenTransfer)
+	if err := _AnalyzerToken.contract.UnpackLog(event, "Transfer", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
-	"os"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -29,32 +27,10 @@ type ERC20Contract struct {
 
 // NewERC20Contract создает новый экземпляр контракта ERC20
 func NewERC20Contract(address common.Address, backend bind.ContractBackend) (*ERC20Contract, error) {
-	// Пытаемся найти ABI файл в разных местах
-	abiPaths := []string{
-		"blockchain/artifacts/contracts/Token.sol/AnalyzerToken.json",
-		"../blockchain/artifacts/contracts/Token.sol/AnalyzerToken.json",
-		"../../blockchain/artifacts/contracts/Token.sol/AnalyzerToken.json",
-	}
-
-	var artifact ContractArtifact
-	var err error
-
-	for _, path := range abiPaths {
-		data, err := os.ReadFile(path)
-		if err == nil {
-			if err := json.Unmarshal(data, &artifact); err == nil {
-				break
-			}
-		}
-	}
-
-	if artifact.Abi == nil {
-		return nil, fmt.Errorf("не удалось найти или прочитать файл ABI")
-	}
-
-	parsed, err := abi.JSON(strings.NewReader(string(artifact.Abi)))
+	// Используем встроенный ABI (или внешний артефакт, если задан через SetArtifactPath)
+	parsed, err := LoadAnalyzerTokenABI()
 	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга ABI: %v", err)
+		return nil, err
 	}
 
 	contract := bind.NewBoundContract(address, parsed, backend, backend, backend)
@@ -76,10 +52,39 @@ func (e *ERC20Contract) GetAddress() common.Address {
 	return e.address
 }
 
+// latestOpts возвращает параметры вызова для последнего блока
+func latestOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx}
+}
+
+// blockOpts возвращает параметры вызова для состояния на указанном блоке
+func blockOpts(ctx context.Context, blockNumber *big.Int) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: blockNumber}
+}
+
+// hashOpts возвращает параметры вызова для состояния на блоке с указанным хешем
+func hashOpts(ctx context.Context, blockHash common.Hash) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockHash: blockHash}
+}
+
 // BalanceOf возвращает баланс токенов для указанного адреса
 func (e *ERC20Contract) BalanceOf(ctx context.Context, account common.Address) (*big.Int, error) {
+	return e.balanceOf(latestOpts(ctx), account)
+}
+
+// BalanceOfAtBlock возвращает баланс токенов на указанном блоке
+func (e *ERC20Contract) BalanceOfAtBlock(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return e.balanceOf(blockOpts(ctx, blockNumber), account)
+}
+
+// BalanceOfAtHash возвращает баланс токенов на блоке с указанным хешем
+func (e *ERC20Contract) BalanceOfAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (*big.Int, error) {
+	return e.balanceOf(hashOpts(ctx, blockHash), account)
+}
+
+func (e *ERC20Contract) balanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error) {
 	var out []interface{}
-	err := e.Call(nil, &out, "balanceOf", account)
+	err := e.Call(opts, &out, "balanceOf", account)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения баланса: %v", err)
 	}
@@ -88,8 +93,22 @@ func (e *ERC20Contract) BalanceOf(ctx context.Context, account common.Address) (
 
 // TotalSupply возвращает общее количество токенов
 func (e *ERC20Contract) TotalSupply(ctx context.Context) (*big.Int, error) {
+	return e.totalSupply(latestOpts(ctx))
+}
+
+// TotalSupplyAtBlock возвращает общее количество токенов на указанном блоке
+func (e *ERC20Contract) TotalSupplyAtBlock(ctx context.Context, blockNumber *big.Int) (*big.Int, error) {
+	return e.totalSupply(blockOpts(ctx, blockNumber))
+}
+
+// TotalSupplyAtHash возвращает общее количество токенов на блоке с указанным хешем
+func (e *ERC20Contract) TotalSupplyAtHash(ctx context.Context, blockHash common.Hash) (*big.Int, error) {
+	return e.totalSupply(hashOpts(ctx, blockHash))
+}
+
+func (e *ERC20Contract) totalSupply(opts *bind.CallOpts) (*big.Int, error) {
 	var out []interface{}
-	err := e.Call(nil, &out, "totalSupply")
+	err := e.Call(opts, &out, "totalSupply")
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения total supply: %v", err)
 	}
@@ -98,8 +117,22 @@ func (e *ERC20Contract) TotalSupply(ctx context.Context) (*big.Int, error) {
 
 // Decimals возвращает количество десятичных знаков токена
 func (e *ERC20Contract) Decimals(ctx context.Context) (uint8, error) {
+	return e.decimals(latestOpts(ctx))
+}
+
+// DecimalsAtBlock возвращает количество десятичных знаков токена на указанном блоке
+func (e *ERC20Contract) DecimalsAtBlock(ctx context.Context, blockNumber *big.Int) (uint8, error) {
+	return e.decimals(blockOpts(ctx, blockNumber))
+}
+
+// DecimalsAtHash возвращает количество десятичных знаков токена на блоке с указанным хешем
+func (e *ERC20Contract) DecimalsAtHash(ctx context.Context, blockHash common.Hash) (uint8, error) {
+	return e.decimals(hashOpts(ctx, blockHash))
+}
+
+func (e *ERC20Contract) decimals(opts *bind.CallOpts) (uint8, error) {
 	var out []interface{}
-	err := e.Call(nil, &out, "decimals")
+	err := e.Call(opts, &out, "decimals")
 	if err != nil {
 		return 0, fmt.Errorf("ошибка получения decimals: %v", err)
 	}
@@ -108,8 +141,22 @@ func (e *ERC20Contract) Decimals(ctx context.Context) (uint8, error) {
 
 // Symbol возвращает символ токена
 func (e *ERC20Contract) Symbol(ctx context.Context) (string, error) {
+	return e.symbol(latestOpts(ctx))
+}
+
+// SymbolAtBlock возвращает символ токена на указанном блоке
+func (e *ERC20Contract) SymbolAtBlock(ctx context.Context, blockNumber *big.Int) (string, error) {
+	return e.symbol(blockOpts(ctx, blockNumber))
+}
+
+// SymbolAtHash возвращает символ токена на блоке с указанным хешем
+func (e *ERC20Contract) SymbolAtHash(ctx context.Context, blockHash common.Hash) (string, error) {
+	return e.symbol(hashOpts(ctx, blockHash))
+}
+
+func (e *ERC20Contract) symbol(opts *bind.CallOpts) (string, error) {
 	var out []interface{}
-	err := e.Call(nil, &out, "symbol")
+	err := e.Call(opts, &out, "symbol")
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения symbol: %v", err)
 	}
@@ -118,8 +165,22 @@ func (e *ERC20Contract) Symbol(ctx context.Context) (string, error) {
 
 // Name возвращает имя токена
 func (e *ERC20Contract) Name(ctx context.Context) (string, error) {
+	return e.name(latestOpts(ctx))
+}
+
+// NameAtBlock возвращает имя токена на указанном блоке
+func (e *ERC20Contract) NameAtBlock(ctx context.Context, blockNumber *big.Int) (string, error) {
+	return e.name(blockOpts(ctx, blockNumber))
+}
+
+// NameAtHash возвращает имя токена на блоке с указанным хешем
+func (e *ERC20Contract) NameAtHash(ctx context.Context, blockHash common.Hash) (string, error) {
+	return e.name(hashOpts(ctx, blockHash))
+}
+
+func (e *ERC20Contract) name(opts *bind.CallOpts) (string, error) {
 	var out []interface{}
-	err := e.Call(nil, &out, "name")
+	err := e.Call(opts, &out, "name")
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения name: %v", err)
 	}
@@ -0,0 +1,358 @@
+package contracts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrNoSelector      = errors.New("input не содержит селектора метода")
+	ErrUnknownSelector = errors.New("неизвестный селектор метода")
+)
+
+// DecodedArg - один декодированный аргумент вызова
+type DecodedArg struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// DecodedMethod - результат декодирования input данных транзакции
+type DecodedMethod struct {
+	Selector  string       `json:"selector"`
+	Name      string       `json:"name"`
+	Signature string       `json:"signature"`
+	Args      []DecodedArg `json:"args"`
+}
+
+// MethodDecoder декодирует вызовы по загруженным ABI и базе текстовых сигнатур
+type MethodDecoder struct {
+	mu      sync.RWMutex
+	methods map[[4]byte][]abi.Method // кандидаты по селектору, методы из ABI идут первыми
+}
+
+func NewMethodDecoder(abis ...abi.ABI) *MethodDecoder {
+	d := &MethodDecoder{methods: make(map[[4]byte][]abi.Method)}
+	for _, parsed := range abis {
+		d.AddABI(parsed)
+	}
+	return d
+}
+
+// AddABI добавляет все методы ABI; они имеют приоритет над сигнатурами без имен аргументов
+func (d *MethodDecoder) AddABI(parsed abi.ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, method := range parsed.Methods {
+		var selector [4]byte
+		copy(selector[:], method.ID)
+		if d.hasSignature(selector, method.Sig) {
+			continue
+		}
+		d.methods[selector] = append([]abi.Method{method}, d.methods[selector]...)
+	}
+}
+
+// AddSignature добавляет текстовую сигнатуру вида "transfer(address,uint256)"
+func (d *MethodDecoder) AddSignature(signature string) error {
+	method, err := methodFromSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var selector [4]byte
+	copy(selector[:], method.ID)
+	if !d.hasSignature(selector, method.Sig) {
+		d.methods[selector] = append(d.methods[selector], method)
+	}
+	return nil
+}
+
+// Decode определяет метод и аргументы по input данным транзакции
+func (d *MethodDecoder) Decode(input []byte) (*DecodedMethod, error) {
+	if len(input) < 4 {
+		return nil, ErrNoSelector
+	}
+
+	var selector [4]byte
+	copy(selector[:], input[:4])
+
+	d.mu.RLock()
+	candidates := d.methods[selector]
+	d.mu.RUnlock()
+
+	if len(candidates) == 0 {
+		return nil, ErrUnknownSelector
+	}
+
+	// При коллизии селекторов берем первого кандидата, чьи аргументы корректно распаковываются
+	var lastErr error
+	for _, method := range candidates {
+		values, err := method.Inputs.Unpack(input[4:])
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		args := make([]DecodedArg, len(method.Inputs))
+		for i, input := range method.Inputs {
+			name := input.Name
+			if name == "" {
+				name = fmt.Sprintf("arg%d", i)
+			}
+			args[i] = DecodedArg{
+				Name:  name,
+				Type:  input.Type.String(),
+				Value: jsonValue(values[i]),
+			}
+		}
+
+		return &DecodedMethod{
+			Selector:  "0x" + hex.EncodeToString(selector[:]),
+			Name:      method.RawName,
+			Signature: method.Sig,
+			Args:      args,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("ошибка распаковки аргументов: %v", lastErr)
+}
+
+// hasSignature вызывается под d.mu
+func (d *MethodDecoder) hasSignature(selector [4]byte, signature string) bool {
+	for _, method := range d.methods[selector] {
+		if method.Sig == signature {
+			return true
+		}
+	}
+	return false
+}
+
+// SignatureSelector вычисляет селектор (0x + 4 байта) для текстовой сигнатуры
+func SignatureSelector(signature string) (string, error) {
+	method, err := methodFromSignature(signature)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(method.ID), nil
+}
+
+// ParseSignatureFile разбирает файл сигнатур: JSON объект {"0xa9059cbb": "transfer(address,uint256)"},
+// JSON массив сигнатур или текст по одной сигнатуре в строке (допускается префикс с селектором)
+func ParseSignatureFile(data []byte) ([]string, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	switch data[0] {
+	case '{':
+		var bySelector map[string]string
+		if err := json.Unmarshal(data, &bySelector); err != nil {
+			return nil, fmt.Errorf("ошибка чтения JSON сигнатур: %v", err)
+		}
+		signatures := make([]string, 0, len(bySelector))
+		for _, signature := range bySelector {
+			signatures = append(signatures, signature)
+		}
+		return signatures, nil
+	case '[':
+		var signatures []string
+		if err := json.Unmarshal(data, &signatures); err != nil {
+			return nil, fmt.Errorf("ошибка чтения JSON сигнатур: %v", err)
+		}
+		return signatures, nil
+	}
+
+	var signatures []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Формат "0xa9059cbb transfer(address,uint256)"
+		if fields := strings.Fields(line); len(fields) == 2 && strings.HasPrefix(fields[0], "0x") {
+			line = fields[1]
+		}
+		signatures = append(signatures, line)
+	}
+	return signatures, scanner.Err()
+}
+
+// methodFromSignature строит abi.Method по текстовой сигнатуре (аргументы без имен)
+func methodFromSignature(signature string) (abi.Method, error) {
+	signature = strings.ReplaceAll(strings.TrimSpace(signature), " ", "")
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return abi.Method{}, fmt.Errorf("неверная сигнатура %q", signature)
+	}
+
+	name := signature[:open]
+	types, err := splitTypes(signature[open+1 : len(signature)-1])
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("неверная сигнатура %q: %v", signature, err)
+	}
+
+	inputs := make(abi.Arguments, len(types))
+	for i, typeStr := range types {
+		typ, err := parseType(typeStr)
+		if err != nil {
+			return abi.Method{}, fmt.Errorf("неверная сигнатура %q: %v", signature, err)
+		}
+		inputs[i] = abi.Argument{Type: typ}
+	}
+
+	return abi.NewMethod(name, name, abi.Function, "nonpayable", false, false, inputs, nil), nil
+}
+
+// parseType разбирает тип аргумента, включая кортежи вида (address,uint256)[]
+func parseType(typeStr string) (abi.Type, error) {
+	if !strings.HasPrefix(typeStr, "(") {
+		return abi.NewType(typeStr, "", nil)
+	}
+
+	closing := matchingParen(typeStr)
+	if closing < 0 {
+		return abi.Type{}, fmt.Errorf("незакрытый кортеж %q", typeStr)
+	}
+
+	components, err := tupleComponents(typeStr[1:closing])
+	if err != nil {
+		return abi.Type{}, err
+	}
+	return abi.NewType("tuple"+typeStr[closing+1:], "", components)
+}
+
+func tupleComponents(inner string) ([]abi.ArgumentMarshaling, error) {
+	types, err := splitTypes(inner)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]abi.ArgumentMarshaling, len(types))
+	for i, typeStr := range types {
+		component := abi.ArgumentMarshaling{Name: fmt.Sprintf("field%d", i), Type: typeStr}
+		if strings.HasPrefix(typeStr, "(") {
+			closing := matchingParen(typeStr)
+			if closing < 0 {
+				return nil, fmt.Errorf("незакрытый кортеж %q", typeStr)
+			}
+			nested, err := tupleComponents(typeStr[1:closing])
+			if err != nil {
+				return nil, err
+			}
+			component.Type = "tuple" + typeStr[closing+1:]
+			component.Components = nested
+		}
+		components[i] = component
+	}
+	return components, nil
+}
+
+// splitTypes делит список типов по запятым верхнего уровня
+func splitTypes(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	var types []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("лишняя закрывающая скобка")
+			}
+		case ',':
+			if depth == 0 {
+				types = append(types, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("несбалансированные скобки")
+	}
+	return append(types, list[start:]), nil
+}
+
+func matchingParen(s string) int {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// jsonValue приводит распакованное значение к виду, удобному для хранения в JSON
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case string, bool:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()).String()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()).String()
+	case reflect.Array:
+		// Фиксированные байтовые массивы (bytes4, bytes32 и т.д.)
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return "0x" + hex.EncodeToString(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = jsonValue(rv.Index(i).Interface())
+		}
+		return items
+	case reflect.Struct:
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			fields[rv.Type().Field(i).Name] = jsonValue(rv.Field(i).Interface())
+		}
+		return fields
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,258 @@
+package contracts
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed abi/Multicall3.abi
+var multicall3ABI []byte
+
+// Multicall3Address - адрес Multicall3, одинаковый во всех основных сетях
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+const (
+	maxCallsPerBatch = 500 // ограничение на количество вызовов в одном eth_call
+)
+
+// Call описывает один read-вызов в составе батча
+type Call struct {
+	Target       common.Address
+	ABI          abi.ABI
+	Method       string
+	Args         []interface{}
+	AllowFailure bool // ошибка вызова не прерывает весь батч
+}
+
+// CallResult - результат одного вызова из батча
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+	Outputs    []interface{} // распакованные значения по ABI метода
+	Err        error
+}
+
+// multicall3Call соответствует структуре Multicall3.Call3
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result соответствует структуре Multicall3.Result
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Multicall объединяет множество read-вызовов в один eth_call через Multicall3
+type Multicall struct {
+	address common.Address
+	abi     abi.ABI
+	caller  bind.ContractCaller
+}
+
+// LoadMulticall3ABI возвращает ABI Multicall3
+func LoadMulticall3ABI() (abi.ABI, error) {
+	return ParseABI(multicall3ABI)
+}
+
+// NewMulticall создает клиента Multicall3 по указанному адресу
+func NewMulticall(address common.Address, caller bind.ContractCaller) (*Multicall, error) {
+	parsed, err := LoadMulticall3ABI()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Multicall{
+		address: address,
+		abi:     parsed,
+		caller:  caller,
+	}, nil
+}
+
+// GetAddress возвращает адрес контракта Multicall3
+func (m *Multicall) GetAddress() common.Address {
+	return m.address
+}
+
+// IsDeployed проверяет, что по адресу Multicall3 есть контракт
+func (m *Multicall) IsDeployed(ctx context.Context) (bool, error) {
+	code, err := m.caller.CodeAt(ctx, m.address, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// Aggregate выполняет вызовы батчами на указанном блоке (nil - последний блок).
+// Результаты возвращаются в порядке вызовов; неуспешные вызовы с AllowFailure отмечаются в CallResult.Err.
+func (m *Multicall) Aggregate(ctx context.Context, calls []Call, blockNumber *big.Int) ([]CallResult, error) {
+	results := make([]CallResult, 0, len(calls))
+
+	for start := 0; start < len(calls); start += maxCallsPerBatch {
+		end := start + maxCallsPerBatch
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		batch, err := m.aggregateBatch(ctx, calls[start:end], blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+	}
+
+	return results, nil
+}
+
+func (m *Multicall) aggregateBatch(ctx context.Context, calls []Call, blockNumber *big.Int) ([]CallResult, error) {
+	packed := make([]multicall3Call, len(calls))
+	for i, call := range calls {
+		callData, err := call.ABI.Pack(call.Method, call.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка упаковки вызова %s: %v", call.Method, err)
+		}
+		packed[i] = multicall3Call{
+			Target:       call.Target,
+			AllowFailure: call.AllowFailure,
+			CallData:     callData,
+		}
+	}
+
+	input, err := m.abi.Pack("aggregate3", packed)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка упаковки aggregate3: %v", err)
+	}
+
+	output, err := m.caller.CallContract(ctx, ethereum.CallMsg{To: &m.address, Data: input}, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка вызова Multicall3: %v", err)
+	}
+
+	unpacked, err := m.abi.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка распаковки aggregate3: %v", err)
+	}
+
+	raw := *abi.ConvertType(unpacked[0], new([]multicall3Result)).(*[]multicall3Result)
+	if len(raw) != len(calls) {
+		return nil, fmt.Errorf("Multicall3 вернул %d результатов вместо %d", len(raw), len(calls))
+	}
+
+	results := make([]CallResult, len(calls))
+	for i, call := range calls {
+		results[i] = CallResult{
+			Success:    raw[i].Success,
+			ReturnData: raw[i].ReturnData,
+		}
+
+		if !raw[i].Success {
+			results[i].Err = fmt.Errorf("вызов %s на %s завершился ошибкой", call.Method, call.Target.Hex())
+			continue
+		}
+
+		outputs, err := call.ABI.Unpack(call.Method, raw[i].ReturnData)
+		if err != nil {
+			results[i].Success = false
+			results[i].Err = fmt.Errorf("ошибка распаковки %s на %s: %v", call.Method, call.Target.Hex(), err)
+			continue
+		}
+		results[i].Outputs = outputs
+	}
+
+	return results, nil
+}
+
+// BalancesOf получает балансы токена для множества держателей одним запросом.
+// Для держателей, по которым вызов не удался, баланс отсутствует в результате.
+func (m *Multicall) BalancesOf(ctx context.Context, token common.Address, holders []common.Address, blockNumber *big.Int) (map[common.Address]*big.Int, error) {
+	erc20, err := LoadERC20ABI()
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]Call, len(holders))
+	for i, holder := range holders {
+		calls[i] = Call{
+			Target:       token,
+			ABI:          erc20,
+			Method:       "balanceOf",
+			Args:         []interface{}{holder},
+			AllowFailure: true,
+		}
+	}
+
+	results, err := m.Aggregate(ctx, calls, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[common.Address]*big.Int, len(holders))
+	for i, result := range results {
+		if result.Err != nil || len(result.Outputs) == 0 {
+			continue
+		}
+		if balance, ok := result.Outputs[0].(*big.Int); ok {
+			balances[holders[i]] = balance
+		}
+	}
+
+	return balances, nil
+}
+
+// EthBalancesOf получает балансы ETH множества адресов одним запросом через Multicall3.getEthBalance.
+// Для адресов, по которым вызов не удался, баланс отсутствует в результате.
+func (m *Multicall) EthBalancesOf(ctx context.Context, holders []common.Address, blockNumber *big.Int) (map[common.Address]*big.Int, error) {
+	calls := make([]Call, len(holders))
+	for i, holder := range holders {
+		calls[i] = Call{
+			Target:       m.address,
+			ABI:          m.abi,
+			Method:       "getEthBalance",
+			Args:         []interface{}{holder},
+			AllowFailure: true,
+		}
+	}
+
+	results, err := m.Aggregate(ctx, calls, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[common.Address]*big.Int, len(holders))
+	for i, result := range results {
+		if result.Err != nil || len(result.Outputs) == 0 {
+			continue
+		}
+		if balance, ok := result.Outputs[0].(*big.Int); ok {
+			balances[holders[i]] = balance
+		}
+	}
+
+	return balances, nil
+}
+
+// TokenMetadataCalls формирует вызовы decimals и symbol для списка токенов (по два вызова на токен)
+func TokenMetadataCalls(tokens []common.Address) ([]Call, error) {
+	erc20, err := LoadERC20ABI()
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]Call, 0, len(tokens)*2)
+	for _, token := range tokens {
+		calls = append(calls,
+			Call{Target: token, ABI: erc20, Method: "decimals", AllowFailure: true},
+			Call{Target: token, ABI: erc20, Method: "symbol", AllowFailure: true},
+		)
+	}
+	return calls, nil
+}
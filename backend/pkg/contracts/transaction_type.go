@@ -0,0 +1,37 @@
+package contracts
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TransactionType соответствует enum AnalyzerToken.TransactionType
+type TransactionType uint8
+
+const (
+	TxTypeTransfer   TransactionType = iota // Обычный перевод
+	TxTypeDeposit                           // Пополнение
+	TxTypeWithdrawal                        // Вывод
+	TxTypeReward                            // Награда/бонус
+	TxTypeFee                               // Комиссия
+)
+
+// TransactionRecordedTopic - topic0 события AnalyzerToken.TransactionRecorded
+var TransactionRecordedTopic = crypto.Keccak256Hash([]byte("TransactionRecorded(address,address,uint256,uint256,uint8,bytes32)"))
+
+// IsRecordedTransfer проверяет, что Transfer попал в статистику AnalyzerToken: transfer и transferWithInfo
+// выпускают TransactionRecorded следующим логом того же контракта, а transferFrom, mint и burn - нет
+func IsRecordedTransfer(transfer, next *types.Log) bool {
+	return next != nil &&
+		next.TxHash == transfer.TxHash &&
+		next.Address == transfer.Address &&
+		next.Index == transfer.Index+1 &&
+		len(next.Topics) > 0 && next.Topics[0] == TransactionRecordedTopic
+}
+
+// DescriptionToBytes32 упаковывает описание перевода в bytes32 (лишнее обрезается)
+func DescriptionToBytes32(description string) [32]byte {
+	var out [32]byte
+	copy(out[:], description)
+	return out
+}
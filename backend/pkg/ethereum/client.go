@@ -2,6 +2,7 @@ package ethereum
 
 import (
 	"backend/configs"
+	"backend/pkg/contracts"
 	"context"
 	"crypto/ecdsa"
 	"math/big"
@@ -18,6 +19,7 @@ type Client struct {
 	publicKey  *ecdsa.PublicKey
 	address    common.Address
 	chainID    *big.Int
+	multicall  common.Address
 }
 
 func NewClient(ctx context.Context, cfg *configs.Config) (*Client, error) {
@@ -43,6 +45,12 @@ func NewClient(ctx context.Context, cfg *configs.Config) (*Client, error) {
 
 	chainID := big.NewInt(cfg.Ethereum.ChainID)
 
+	// В локальной сети Hardhat Multicall3 деплоится отдельно (scripts/deploy-multicall.js)
+	multicall := contracts.Multicall3Address
+	if cfg.Ethereum.MulticallAddress != "" {
+		multicall = common.HexToAddress(cfg.Ethereum.MulticallAddress)
+	}
+
 	logrus.Infof("Подключение к Ethereum RPC: %s", cfg.Ethereum.RPCEndpoint)
 	logrus.Infof("Chain ID: %d", cfg.Ethereum.ChainID)
 
@@ -52,6 +60,7 @@ func NewClient(ctx context.Context, cfg *configs.Config) (*Client, error) {
 		publicKey:  publicKey,
 		address:    address,
 		chainID:    chainID,
+		multicall:  multicall,
 	}, nil
 }
 
@@ -67,6 +76,11 @@ func (c *Client) GetChainID() *big.Int {
 	return c.chainID
 }
 
+// GetMulticall возвращает клиента Multicall3 для батчинга read-вызовов
+func (c *Client) GetMulticall() (*contracts.Multicall, error) {
+	return contracts.NewMulticall(c.multicall, c.client)
+}
+
 func (c *Client) GetPrivateKey() *ecdsa.PrivateKey {
 	return c.privateKey
 }
@@ -75,6 +89,16 @@ func (c *Client) GetBalance(ctx context.Context, address common.Address) (*big.I
 	return c.client.BalanceAt(ctx, address, nil)
 }
 
+// GetBalanceAtBlock возвращает баланс ETH на указанном блоке
+func (c *Client) GetBalanceAtBlock(ctx context.Context, address common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return c.client.BalanceAt(ctx, address, blockNumber)
+}
+
+// GetBalanceAtHash возвращает баланс ETH на блоке с указанным хешем
+func (c *Client) GetBalanceAtHash(ctx context.Context, address common.Address, blockHash common.Hash) (*big.Int, error) {
+	return c.client.BalanceAtHash(ctx, address, blockHash)
+}
+
 func (c *Client) Close() {
 	c.client.Close()
 }
@@ -0,0 +1,82 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// InternalTransfer - перевод ETH внутри выполнения транзакции (CALL с value, CREATE, SELFDESTRUCT)
+type InternalTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+// callFrame соответствует кадру вызова callTracer
+type callFrame struct {
+	Type  string          `json:"type"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Error string          `json:"error"`
+	Calls []callFrame     `json:"calls"`
+}
+
+// TraceInternalTransfers возвращает внутренние переводы ETH транзакции через debug_traceTransaction (callTracer).
+// Верхний кадр (value самой транзакции) не включается; откаченные кадры и их потомки пропускаются
+func (c *Client) TraceInternalTransfers(ctx context.Context, txHash common.Hash) ([]InternalTransfer, error) {
+	var root callFrame
+	err := c.client.Client().CallContext(ctx, &root, "debug_traceTransaction", txHash,
+		map[string]interface{}{"tracer": "callTracer"})
+	if err != nil {
+		return nil, err
+	}
+
+	if root.Error != "" {
+		return nil, nil
+	}
+
+	var transfers []InternalTransfer
+	for _, call := range root.Calls {
+		collectInternalTransfers(call, &transfers)
+	}
+	return transfers, nil
+}
+
+func collectInternalTransfers(frame callFrame, transfers *[]InternalTransfer) {
+	if frame.Error != "" {
+		return
+	}
+
+	// DELEGATECALL, STATICCALL и CALLCODE не переводят ETH другому адресу
+	switch strings.ToUpper(frame.Type) {
+	case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+		if frame.To != nil && frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+			*transfers = append(*transfers, InternalTransfer{
+				From:  frame.From,
+				To:    *frame.To,
+				Value: new(big.Int).Set(frame.Value.ToInt()),
+			})
+		}
+	}
+
+	for _, call := range frame.Calls {
+		collectInternalTransfers(call, transfers)
+	}
+}
+
+// IsMethodNotSupported проверяет, что нода не поддерживает RPC метод (например, debug_* отключен)
+func IsMethodNotSupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "method not found") ||
+		strings.Contains(message, "does not exist") ||
+		strings.Contains(message, "not supported") ||
+		strings.Contains(message, "not available")
+}
@@ -0,0 +1,447 @@
+package ethereum
+
+import (
+	"backend/pkg/contracts"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStuckTimeout   = 2 * time.Minute
+	pendingCheckInterval  = 5 * time.Second
+	gasLimitMarginPercent = 20        // запас к оценке газа
+	feeBumpPercent        = 125       // новая комиссия при замене, % от старой (ноды требуют минимум +10%)
+	sentHashesRetention   = time.Hour // сколько хранить хеши всех версий транзакции после подтверждения nonce
+)
+
+// pendingTx - отправленная, но еще не включенная в блок транзакция
+type pendingTx struct {
+	tx     *types.Transaction
+	sentAt time.Time
+}
+
+// sentNonce - хеши всех отправленных версий транзакции с одним nonce (исходной и замен)
+type sentNonce struct {
+	hashes      []common.Hash
+	confirmedAt time.Time // когда nonce оказался подтвержденным; нулевое - еще нет
+}
+
+// TxManager собирает, подписывает (EIP-1559) и отправляет транзакции от ключа клиента,
+// безопасно выдает nonce при конкурентной отправке и заменяет зависшие транзакции
+type TxManager struct {
+	client       *Client
+	tokenABI     abi.ABI
+	stuckTimeout time.Duration
+
+	mu        sync.Mutex
+	nextNonce *uint64
+	pending   map[uint64]*pendingTx
+	sent      map[uint64]*sentNonce
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTxManager(client *Client) (*TxManager, error) {
+	if client.GetPrivateKey() == nil {
+		return nil, fmt.Errorf("приватный ключ не задан (ETH_PRIVATE_KEY)")
+	}
+
+	tokenABI, err := contracts.LoadAnalyzerTokenABI()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxManager{
+		client:       client,
+		tokenABI:     tokenABI,
+		stuckTimeout: defaultStuckTimeout,
+		pending:      make(map[uint64]*pendingTx),
+		sent:         make(map[uint64]*sentNonce),
+	}, nil
+}
+
+// SetStuckTimeout задает время, после которого неподтвержденная транзакция считается зависшей
+func (m *TxManager) SetStuckTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		m.stuckTimeout = timeout
+	}
+}
+
+// Start запускает отслеживание отправленных транзакций и замену зависших
+func (m *TxManager) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(pendingCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.checkPending(ctx); err != nil {
+					logrus.Errorf("Ошибка проверки отправленных транзакций: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (m *TxManager) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.wg.Wait()
+	}
+}
+
+// SendETH отправляет ETH на указанный адрес
+func (m *TxManager) SendETH(ctx context.Context, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return m.Send(ctx, to, amount, nil)
+}
+
+// SendToken отправляет ERC20 токены вызовом transfer
+func (m *TxManager) SendToken(ctx context.Context, token, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	data, err := m.tokenABI.Pack("transfer", to, amount)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка упаковки transfer: %v", err)
+	}
+	return m.Send(ctx, token, big.NewInt(0), data)
+}
+
+// TransferWithInfo отправляет токены AnalyzerToken с типом и описанием перевода
+func (m *TxManager) TransferWithInfo(ctx context.Context, token, to common.Address, amount *big.Int, txType contracts.TransactionType, description string) (*types.Transaction, error) {
+	data, err := m.tokenABI.Pack("transferWithInfo", to, amount, uint8(txType), contracts.DescriptionToBytes32(description))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка упаковки transferWithInfo: %v", err)
+	}
+	return m.Send(ctx, token, big.NewInt(0), data)
+}
+
+// Send собирает, оценивает, подписывает и отправляет EIP-1559 транзакцию
+func (m *TxManager) Send(ctx context.Context, to common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	gasLimit, err := m.client.GetClient().EstimateGas(ctx, eth.CallMsg{
+		From:  m.client.GetAddress(),
+		To:    &to,
+		Value: value,
+		Data:  data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка оценки газа: %v", err)
+	}
+	gasLimit = gasLimit * (100 + gasLimitMarginPercent) / 100
+
+	tipCap, feeCap, err := m.suggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nonce резервируется под блокировкой вместе с отправкой, чтобы параллельные вызовы не получили одинаковый
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, err := m.reserveNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   m.client.GetChainID(),
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+
+	signed, err := m.signAndSend(ctx, tx)
+	if err != nil {
+		// Nonce не использован - при следующей отправке перечитываем его из сети
+		m.nextNonce = nil
+		return nil, err
+	}
+
+	*m.nextNonce = nonce + 1
+	m.pending[nonce] = &pendingTx{tx: signed, sentAt: time.Now()}
+	m.sent[nonce] = &sentNonce{hashes: []common.Hash{signed.Hash()}}
+
+	logrus.Infof("Отправлена транзакция %s (nonce %d) на %s", signed.Hash().Hex(), nonce, to.Hex())
+	return signed, nil
+}
+
+// WaitMined ожидает включения транзакции в блок.
+// Если транзакция была заменена, в блок может попасть любая версия с тем же nonce - проверяются все.
+func (m *TxManager) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := m.FindReceipt(ctx, tx.Nonce(), tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// FindReceipt ищет receipt среди всех отправленных версий транзакции с nonce и дополнительно переданных хешей.
+// Возвращает nil без ошибки, если ни одна версия еще не включена в блок
+func (m *TxManager) FindReceipt(ctx context.Context, nonce uint64, known ...common.Hash) (*types.Receipt, error) {
+	hashes := append(m.SentHashes(nonce), known...)
+
+	checked := make(map[common.Hash]bool, len(hashes))
+	for _, hash := range hashes {
+		if checked[hash] {
+			continue
+		}
+		checked[hash] = true
+
+		receipt, err := m.client.GetClient().TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, eth.NotFound) {
+			logrus.Debugf("Ошибка получения receipt %s: %v", hash.Hex(), err)
+		}
+	}
+
+	return nil, nil
+}
+
+// SentHashes возвращает хеши всех отправленных версий транзакции с nonce, начиная с исходной.
+// После подтверждения nonce хеши хранятся еще sentHashesRetention
+func (m *TxManager) SentHashes(nonce uint64) []common.Hash {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, exists := m.sent[nonce]
+	if !exists {
+		return nil
+	}
+	return append([]common.Hash(nil), s.hashes...)
+}
+
+// PendingByNonce возвращает актуальную (с учетом замен) версию неподтвержденной транзакции
+func (m *TxManager) PendingByNonce(nonce uint64) (*types.Transaction, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, exists := m.pending[nonce]
+	if !exists {
+		return nil, false
+	}
+	return p.tx, true
+}
+
+// PendingCount возвращает количество неподтвержденных транзакций
+func (m *TxManager) PendingCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
+}
+
+// reserveNonce выдает следующий nonce; вызывается под m.mu
+func (m *TxManager) reserveNonce(ctx context.Context) (uint64, error) {
+	if m.nextNonce == nil {
+		nonce, err := m.client.GetClient().PendingNonceAt(ctx, m.client.GetAddress())
+		if err != nil {
+			return 0, fmt.Errorf("ошибка получения nonce: %v", err)
+		}
+		m.nextNonce = &nonce
+	}
+	return *m.nextNonce, nil
+}
+
+func (m *TxManager) suggestFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	tipCap, err := m.client.GetClient().SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка получения gas tip: %v", err)
+	}
+
+	header, err := m.client.GetClient().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка получения заголовка блока: %v", err)
+	}
+
+	baseFee := header.BaseFee
+	if baseFee == nil {
+		baseFee = big.NewInt(0)
+	}
+
+	// Запас на рост base fee в следующих блоках: 2 * baseFee + tip
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tipCap)
+	return tipCap, feeCap, nil
+}
+
+func (m *TxManager) signAndSend(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(m.client.GetChainID())
+	signed, err := types.SignTx(tx, signer, m.client.GetPrivateKey())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подписи транзакции: %v", err)
+	}
+
+	if err := m.client.GetClient().SendTransaction(ctx, signed); err != nil {
+		return nil, fmt.Errorf("ошибка отправки транзакции: %v", err)
+	}
+
+	return signed, nil
+}
+
+// checkPending убирает подтвержденные транзакции и заменяет зависшие.
+// Обращения к ноде выполняются без блокировки, чтобы медленная нода не задерживала Send
+func (m *TxManager) checkPending(ctx context.Context) error {
+	m.mu.Lock()
+	snapshot := make(map[uint64]*pendingTx, len(m.pending))
+	for nonce, p := range m.pending {
+		snapshot[nonce] = p
+	}
+	m.mu.Unlock()
+
+	if len(snapshot) == 0 {
+		m.forgetConfirmed()
+		return nil
+	}
+
+	// Все транзакции с nonce ниже подтвержденного уже включены в блоки (или заменены)
+	confirmedNonce, err := m.client.GetClient().NonceAt(ctx, m.client.GetAddress(), nil)
+	if err != nil {
+		return fmt.Errorf("ошибка получения nonce: %v", err)
+	}
+
+	var confirmed []uint64
+	replacements := make(map[uint64]*types.Transaction)
+	for nonce, p := range snapshot {
+		if nonce < confirmedNonce {
+			confirmed = append(confirmed, nonce)
+			continue
+		}
+
+		if time.Since(p.sentAt) < m.stuckTimeout {
+			continue
+		}
+
+		replacement, err := m.bumpFees(ctx, p.tx)
+		if err != nil {
+			if isNonceTooLow(err) {
+				confirmed = append(confirmed, nonce)
+				continue
+			}
+			logrus.Errorf("Ошибка замены зависшей транзакции %s: %v", p.tx.Hash().Hex(), err)
+			continue
+		}
+
+		logrus.Warnf("Транзакция %s (nonce %d) зависла, заменена на %s с повышенной комиссией",
+			p.tx.Hash().Hex(), nonce, replacement.Hash().Hex())
+		replacements[nonce] = replacement
+	}
+
+	m.mu.Lock()
+	now := time.Now()
+	for _, nonce := range confirmed {
+		delete(m.pending, nonce)
+		if s, exists := m.sent[nonce]; exists && s.confirmedAt.IsZero() {
+			s.confirmedAt = now
+		}
+	}
+	for nonce, replacement := range replacements {
+		// Замена уже в сети, поэтому ее хеш запоминаем в любом случае
+		if s, exists := m.sent[nonce]; exists {
+			s.hashes = append(s.hashes, replacement.Hash())
+		}
+		if p, exists := m.pending[nonce]; exists && p == snapshot[nonce] {
+			m.pending[nonce] = &pendingTx{tx: replacement, sentAt: now}
+		}
+	}
+	m.mu.Unlock()
+
+	m.forgetConfirmed()
+	return nil
+}
+
+// forgetConfirmed удаляет хеши транзакций, nonce которых подтвержден дольше sentHashesRetention
+func (m *TxManager) forgetConfirmed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for nonce, s := range m.sent {
+		if !s.confirmedAt.IsZero() && time.Since(s.confirmedAt) > sentHashesRetention {
+			delete(m.sent, nonce)
+		}
+	}
+}
+
+// bumpFees переотправляет транзакцию с тем же nonce и повышенными комиссиями
+func (m *TxManager) bumpFees(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	tipCap := bumpFee(tx.GasTipCap())
+	feeCap := bumpFee(tx.GasFeeCap())
+
+	// Если сеть подорожала сильнее, берем текущие рекомендации
+	suggestedTip, suggestedFeeCap, err := m.suggestFees(ctx)
+	if err == nil {
+		if suggestedTip.Cmp(tipCap) > 0 {
+			tipCap = suggestedTip
+		}
+		if suggestedFeeCap.Cmp(feeCap) > 0 {
+			feeCap = suggestedFeeCap
+		}
+	}
+	if feeCap.Cmp(tipCap) < 0 {
+		feeCap = new(big.Int).Set(tipCap)
+	}
+
+	replacement := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   m.client.GetChainID(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	})
+
+	return m.signAndSend(ctx, replacement)
+}
+
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(feeBumpPercent))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func isNonceTooLow(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
@@ -25,17 +25,47 @@ const (
 	defaultPollInterval = 1 * time.Second
 )
 
+// Режимы работы слушателя
+const (
+	ModePolling      = "polling"      // периодический опрос через FilterLogs
+	ModeSubscription = "subscription" // push-подписка через WebSocket
+)
+
 type EventListener struct {
 	client          *ethclient.Client
 	contractAddress common.Address
 	erc20Contract   *contracts.ERC20Contract
 	pollInterval    time.Duration
+	sink            Sink
+	mode            string
+	wsEndpoint      string
+	fromFilter      []common.Address
+	toFilter        []common.Address
+	checkpoint      uint64 // последний блок, события которого переданы в приемник
+	checkpointMux   sync.Mutex
+	checkpoints     CheckpointStore
+	seen            *eventDeduplicator
 	cancel          context.CancelFunc
 	wg              sync.WaitGroup
 }
 
+// CheckpointStore хранит чекпоинт слушателя между перезапусками
+type CheckpointStore interface {
+	// LoadCheckpoint возвращает сохраненный чекпоинт контракта (0 - нет)
+	LoadCheckpoint(contractAddress string) (uint64, error)
+	// SaveCheckpoint сохраняет чекпоинт контракта
+	SaveCheckpoint(contractAddress string, block uint64) error
+}
+
 type ListenerConfig struct {
 	PollInterval time.Duration
+	Sink         Sink             // приемник событий, по умолчанию PostgreSQL
+	Checkpoints  CheckpointStore  // хранилище чекпоинта, по умолчанию PostgreSQL
+	Mode         string           // ModePolling (по умолчанию) или ModeSubscription
+	WSEndpoint   string           // WebSocket endpoint для режима подписки
+	FromFilter   []common.Address // фильтр по отправителю
+	ToFilter     []common.Address // фильтр по получателю
+	StartBlock   uint64           // блок, с которого начинать (0 - с текущего)
 }
 
 func NewEventListener(client *ethclient.Client, contractAddress common.Address) (*EventListener, error) {
@@ -49,6 +79,10 @@ func NewEventListener(client *ethclient.Client, contractAddress common.Address)
 		contractAddress: contractAddress,
 		erc20Contract:   erc20Contract,
 		pollInterval:    defaultPollInterval,
+		sink:            NewDBSink(repositories.DB),
+		checkpoints:     repositories.NewListenerCheckpointRepository(),
+		mode:            ModePolling,
+		seen:            newEventDeduplicator(maxSeenEvents),
 	}, nil
 }
 
@@ -56,12 +90,58 @@ func (el *EventListener) SetConfig(cfg ListenerConfig) {
 	if cfg.PollInterval > 0 {
 		el.pollInterval = cfg.PollInterval
 	}
+	if cfg.Sink != nil {
+		el.sink = cfg.Sink
+	}
+	if cfg.Checkpoints != nil {
+		el.checkpoints = cfg.Checkpoints
+	}
+	if cfg.Mode != "" {
+		el.mode = cfg.Mode
+	}
+	if cfg.WSEndpoint != "" {
+		el.wsEndpoint = cfg.WSEndpoint
+	}
+	if len(cfg.FromFilter) > 0 {
+		el.fromFilter = cfg.FromFilter
+	}
+	if len(cfg.ToFilter) > 0 {
+		el.toFilter = cfg.ToFilter
+	}
+	if cfg.StartBlock > 0 {
+		el.setCheckpoint(cfg.StartBlock - 1)
+	}
 }
 
 func (el *EventListener) StartListening(ctx context.Context) error {
+	// Без явного StartBlock продолжаем с сохраненного чекпоинта, чтобы дозагрузить события, пропущенные за время простоя
+	if el.getCheckpoint() == 0 && el.checkpoints != nil {
+		checkpoint, err := el.checkpoints.LoadCheckpoint(el.contractAddress.Hex())
+		if err != nil {
+			return fmt.Errorf("ошибка загрузки чекпоинта слушателя: %v", err)
+		}
+		if checkpoint > 0 {
+			logrus.Infof("Слушатель продолжает с чекпоинта #%d", checkpoint)
+			el.setCheckpoint(checkpoint)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	el.cancel = cancel
 
+	if el.mode == ModeSubscription {
+		if el.wsEndpoint == "" {
+			cancel()
+			return fmt.Errorf("для режима подписки необходим WebSocket endpoint")
+		}
+
+		logrus.Infof("Начинаем прослушивание событий контракта: %s (subscription mode)", el.contractAddress.Hex())
+
+		el.wg.Add(1)
+		go el.runSubscription(ctx)
+		return nil
+	}
+
 	logrus.Infof("Начинаем прослушивание событий контракта: %s (polling mode)", el.contractAddress.Hex())
 
 	// Используем polling вместо WebSocket подписок
@@ -71,18 +151,15 @@ func (el *EventListener) StartListening(ctx context.Context) error {
 		ticker := time.NewTicker(el.pollInterval)
 		defer ticker.Stop()
 
-		var lastBlock uint64 = 0
-		var lastProcessedEvents = make(map[string]bool)
-
 		for {
 			select {
 			case <-ticker.C:
-				if err := el.pollForEvents(ctx, &lastBlock, lastProcessedEvents); err != nil {
+				if err := el.pollForEvents(ctx); err != nil {
 					logrus.Errorf("Ошибка опроса событий: %v", err)
 					// Делаем retry с exponential backoff
 					for i := 0; i < maxRetries; i++ {
 						time.Sleep(retryDelay * time.Duration(i+1))
-						if err := el.pollForEvents(ctx, &lastBlock, lastProcessedEvents); err == nil {
+						if err := el.pollForEvents(ctx); err == nil {
 							break
 						}
 					}
@@ -102,9 +179,15 @@ func (el *EventListener) Stop() {
 		el.cancel()
 		el.wg.Wait()
 	}
+
+	if el.sink != nil {
+		if err := el.sink.Close(); err != nil {
+			logrus.Errorf("Ошибка закрытия приемника событий: %v", err)
+		}
+	}
 }
 
-func (el *EventListener) pollForEvents(ctx context.Context, lastBlock *uint64, lastProcessedEvents map[string]bool) error {
+func (el *EventListener) pollForEvents(ctx context.Context) error {
 	// Получаем текущий блок
 	header, err := el.client.HeaderByNumber(ctx, nil)
 	if err != nil {
@@ -112,28 +195,35 @@ func (el *EventListener) pollForEvents(ctx context.Context, lastBlock *uint64, l
 	}
 
 	currentBlock := header.Number.Uint64()
+	lastBlock := el.getCheckpoint()
 
-	if *lastBlock == 0 {
-		*lastBlock = currentBlock - confirmations
+	if lastBlock == 0 {
+		if currentBlock > confirmations {
+			el.setCheckpoint(currentBlock - confirmations)
+		}
 		return nil
 	}
 
 	// Проверяем, есть ли достаточно подтверждений
-	if currentBlock < *lastBlock+confirmations {
+	if currentBlock < lastBlock+confirmations {
 		return nil
 	}
 
-	// Обрабатываем только подтвержденные блоки
+	// Обрабатываем только подтвержденные блоки; после простоя догоняем порциями
 	toBlock := currentBlock - confirmations
-	if toBlock <= *lastBlock {
+	if toBlock <= lastBlock {
 		return nil
 	}
+	if toBlock > lastBlock+maxBackfillRange {
+		toBlock = lastBlock + maxBackfillRange
+	}
 
 	// Создаем фильтр для новых событий
 	query := ethereum.FilterQuery{
-		FromBlock: big.NewInt(int64(*lastBlock + 1)),
+		FromBlock: big.NewInt(int64(lastBlock + 1)),
 		ToBlock:   big.NewInt(int64(toBlock)),
 		Addresses: []common.Address{el.contractAddress},
+		Topics:    el.transferTopics(),
 	}
 
 	logs, err := el.client.FilterLogs(ctx, query)
@@ -141,14 +231,45 @@ func (el *EventListener) pollForEvents(ctx context.Context, lastBlock *uint64, l
 		return fmt.Errorf("ошибка фильтрации логов: %v", err)
 	}
 
+	if err := el.handleLogs(ctx, logs); err != nil {
+		return err
+	}
+
+	el.setCheckpoint(toBlock)
+	return nil
+}
+
+// handleLogs декодирует логи, отбрасывает уже переданные события и батчами отдает их в приемник
+func (el *EventListener) handleLogs(ctx context.Context, logs []types.Log) error {
 	// Группируем события для батчинга
 	var transfers []*models.ERC20Transfer
+	var keys []string
+
+	flush := func() error {
+		if err := el.saveTransfers(ctx, transfers); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			el.seen.Add(key)
+		}
+		transfers = transfers[:0]
+		keys = keys[:0]
+		return nil
+	}
+
+	// Время блоков, в которых встретились события
+	blockTimes := make(map[uint64]time.Time)
 
 	// Обрабатываем найденные события
 	for _, vLog := range logs {
+		// Логи, отмененные реорганизацией, пропускаем
+		if vLog.Removed {
+			continue
+		}
+
 		// Проверяем, не обработали ли мы уже это событие
-		eventKey := fmt.Sprintf("%s-%d-%d", vLog.TxHash.Hex(), vLog.BlockNumber, vLog.Index)
-		if lastProcessedEvents[eventKey] {
+		eventKey := eventKey(vLog)
+		if el.seen.Contains(eventKey) {
 			continue
 		}
 
@@ -159,33 +280,52 @@ func (el *EventListener) pollForEvents(ctx context.Context, lastBlock *uint64, l
 		}
 
 		if transfer != nil {
+			// Без времени блока трансфер попал бы не в тот период, поэтому при ошибке повторяем всю пачку
+			if transfer.Timestamp, err = el.blockTime(ctx, vLog.BlockNumber, blockTimes); err != nil {
+				return err
+			}
 			transfers = append(transfers, transfer)
-			lastProcessedEvents[eventKey] = true
+			keys = append(keys, eventKey)
 		}
 
 		// Если накопилось достаточно событий, сохраняем их
 		if len(transfers) >= batchSize {
-			if err := el.saveTransfers(transfers); err != nil {
+			if err := flush(); err != nil {
 				return fmt.Errorf("ошибка сохранения batch трансферов: %v", err)
 			}
-			transfers = transfers[:0]
 		}
 	}
 
 	// Сохраняем оставшиеся события
 	if len(transfers) > 0 {
-		if err := el.saveTransfers(transfers); err != nil {
+		if err := flush(); err != nil {
 			return fmt.Errorf("ошибка сохранения оставшихся трансферов: %v", err)
 		}
 	}
 
-	// Очищаем старые записи в кэше обработанных событий
-	for key := range lastProcessedEvents {
-		delete(lastProcessedEvents, key)
+	return nil
+}
+
+func (el *EventListener) getCheckpoint() uint64 {
+	el.checkpointMux.Lock()
+	defer el.checkpointMux.Unlock()
+	return el.checkpoint
+}
+
+// setCheckpoint сдвигает чекпоинт только вперед и сохраняет его в хранилище
+func (el *EventListener) setCheckpoint(block uint64) {
+	el.checkpointMux.Lock()
+	advanced := block > el.checkpoint
+	if advanced {
+		el.checkpoint = block
 	}
+	el.checkpointMux.Unlock()
 
-	*lastBlock = toBlock
-	return nil
+	if advanced && el.checkpoints != nil {
+		if err := el.checkpoints.SaveCheckpoint(el.contractAddress.Hex(), block); err != nil {
+			logrus.Errorf("Ошибка сохранения чекпоинта слушателя: %v", err)
+		}
+	}
 }
 
 func (el *EventListener) processLog(ctx context.Context, vLog types.Log) (*models.ERC20Transfer, error) {
@@ -207,37 +347,41 @@ func (el *EventListener) processLog(ctx context.Context, vLog types.Log) (*model
 
 	return &models.ERC20Transfer{
 		TransactionHash: vLog.TxHash.Hex(),
+		LogIndex:        vLog.Index,
 		ContractAddress: el.contractAddress.Hex(),
 		From:            from.Hex(),
 		To:              to.Hex(),
-		Value:           amount.String(),
+		Value:           models.NewBigInt(amount),
+		Kind:            models.TransferKindOf(from.Hex(), to.Hex()),
 		BlockNumber:     vLog.BlockNumber,
-		CreatedAt:       time.Now(),
 	}, nil
 }
 
-func (el *EventListener) saveTransfers(transfers []*models.ERC20Transfer) error {
-	if len(transfers) == 0 {
-		return nil
+// blockTime возвращает время блока из заголовка, запоминая его в cache
+func (el *EventListener) blockTime(ctx context.Context, number uint64, cache map[uint64]time.Time) (time.Time, error) {
+	if blockTime, ok := cache[number]; ok {
+		return blockTime, nil
 	}
 
-	// Используем транзакцию для батчинга
-	tx := repositories.DB.Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("ошибка начала транзакции: %v", tx.Error)
+	header, err := el.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ошибка получения заголовка блока #%d: %v", number, err)
 	}
 
-	for _, transfer := range transfers {
-		if err := tx.Create(transfer).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("ошибка сохранения трансфера: %v", err)
-		}
+	blockTime := time.Unix(int64(header.Time), 0)
+	cache[number] = blockTime
+	return blockTime, nil
+}
+
+func (el *EventListener) saveTransfers(ctx context.Context, transfers []*models.ERC20Transfer) error {
+	if len(transfers) == 0 {
+		return nil
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("ошибка коммита транзакции: %v", err)
+	if err := el.sink.Write(ctx, transfers); err != nil {
+		return err
 	}
 
-	logrus.Infof("Сохранено %d ERC20 трансферов", len(transfers))
+	logrus.Infof("Передано %d ERC20 трансферов в приемник", len(transfers))
 	return nil
 }
@@ -0,0 +1,203 @@
+package listeners
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Sink принимает декодированные события от слушателя
+type Sink interface {
+	// Write сохраняет/передает пачку трансферов
+	Write(ctx context.Context, transfers []*models.ERC20Transfer) error
+	// Close освобождает ресурсы приемника
+	Close() error
+}
+
+// DBSink сохраняет трансферы в PostgreSQL. Трансферы записываются неучтенными: статистику, балансы
+// и граф по ним обновляет только анализатор блоков (см. repositories.SaveTransferForAccounting)
+type DBSink struct {
+	db *gorm.DB
+}
+
+func NewDBSink(db *gorm.DB) *DBSink {
+	return &DBSink{db: db}
+}
+
+func (s *DBSink) Write(ctx context.Context, transfers []*models.ERC20Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	// Используем транзакцию для батчинга
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", tx.Error)
+	}
+
+	for _, transfer := range transfers {
+		// Трансфер мог быть уже сохранен анализатором при опросе блоков - такие пропускаем
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(transfer).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("ошибка сохранения трансфера: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("ошибка коммита транзакции: %v", err)
+	}
+
+	return nil
+}
+
+func (s *DBSink) Close() error {
+	return nil
+}
+
+// ChannelSink передает трансферы в Go канал для потребителей внутри процесса
+type ChannelSink struct {
+	ch     chan *models.ERC20Transfer
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewChannelSink(bufferSize int) *ChannelSink {
+	return &ChannelSink{
+		ch: make(chan *models.ERC20Transfer, bufferSize),
+	}
+}
+
+// Events возвращает канал, из которого читают потребители
+func (s *ChannelSink) Events() <-chan *models.ERC20Transfer {
+	return s.ch
+}
+
+func (s *ChannelSink) Write(ctx context.Context, transfers []*models.ERC20Transfer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return fmt.Errorf("канал приемника закрыт")
+	}
+
+	for _, transfer := range transfers {
+		select {
+		case s.ch <- transfer:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (s *ChannelSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+	return nil
+}
+
+// AmountFormatter форматирует сумму токена с учетом decimals и символа
+type AmountFormatter func(contractAddress string, value *big.Int) string
+
+// JSONFileSink пишет трансферы в файл в формате newline-delimited JSON
+type JSONFileSink struct {
+	file      *os.File
+	writer    *bufio.Writer
+	formatter AmountFormatter
+	mu        sync.Mutex
+}
+
+// exportedTransfer - трансфер с отформатированной суммой для экспорта
+type exportedTransfer struct {
+	*models.ERC20Transfer
+	ValueFormatted string `json:"value_formatted,omitempty"`
+}
+
+func NewJSONFileSink(path string) (*JSONFileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла %s: %v", path, err)
+	}
+
+	return &JSONFileSink{
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}, nil
+}
+
+// SetAmountFormatter включает запись отформатированной суммы (value_formatted) рядом с сырым значением
+func (s *JSONFileSink) SetAmountFormatter(formatter AmountFormatter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.formatter = formatter
+}
+
+func (s *JSONFileSink) Write(ctx context.Context, transfers []*models.ERC20Transfer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	encoder := json.NewEncoder(s.writer)
+	for _, transfer := range transfers {
+		record := exportedTransfer{ERC20Transfer: transfer}
+		if s.formatter != nil {
+			record.ValueFormatted = s.formatter(transfer.ContractAddress, transfer.Value.Int())
+		}
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("ошибка записи трансфера в файл: %v", err)
+		}
+	}
+
+	return s.writer.Flush()
+}
+
+func (s *JSONFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	return s.file.Close()
+}
+
+// MultiSink рассылает трансферы сразу в несколько приемников
+type MultiSink struct {
+	sinks []Sink
+}
+
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+func (s *MultiSink) Write(ctx context.Context, transfers []*models.ERC20Transfer) error {
+	for _, sink := range s.sinks {
+		if err := sink.Write(ctx, transfers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MultiSink) Close() error {
+	var firstErr error
+	for _, sink := range s.sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
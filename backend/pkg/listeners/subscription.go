@@ -0,0 +1,280 @@
+package listeners
+
+import (
+	"backend/pkg/contracts"
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	reconnectDelay    = 5 * time.Second
+	maxReconnectDelay = 1 * time.Minute
+	maxBackfillRange  = 1000  // максимальный диапазон блоков в одном запросе FilterLogs
+	maxSeenEvents     = 10000 // размер кэша уже переданных событий
+)
+
+// runSubscription держит подписку на Transfer и переподключается при обрыве
+func (el *EventListener) runSubscription(ctx context.Context) {
+	defer el.wg.Done()
+
+	delay := reconnectDelay
+	for {
+		// После успешного подключения и дозагрузки задержка начинается заново
+		err := el.subscribe(ctx, func() { delay = reconnectDelay })
+		if ctx.Err() != nil {
+			logrus.Info("Остановка прослушивания событий")
+			return
+		}
+
+		logrus.Errorf("Подписка на события прервана: %v. Переподключение через %v", err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			logrus.Info("Остановка прослушивания событий")
+			return
+		}
+
+		// Увеличиваем задержку до следующей попытки
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// subscribe подключается по WebSocket, дозагружает пропущенные блоки и читает события из подписки.
+// onActive вызывается, когда подписка активна и пропущенные события дозагружены
+func (el *EventListener) subscribe(ctx context.Context, onActive func()) error {
+	wsClient, err := ethclient.DialContext(ctx, el.wsEndpoint)
+	if err != nil {
+		return fmt.Errorf("ошибка подключения к %s: %v", el.wsEndpoint, err)
+	}
+	defer wsClient.Close()
+
+	contract, err := contracts.NewERC20Contract(el.contractAddress, wsClient)
+	if err != nil {
+		return fmt.Errorf("ошибка создания контракта: %v", err)
+	}
+
+	// Сначала подписываемся, потом дозагружаем историю: так между ними не остается разрыва,
+	// а пересечение отсекается дедупликацией
+	events := make(chan *contracts.Transfer, batchSize)
+	sub, err := contract.WatchTransfer(&bind.WatchOpts{Context: ctx}, events, el.fromFilter, el.toFilter)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	if err := el.backfill(ctx, wsClient); err != nil {
+		return fmt.Errorf("ошибка дозагрузки пропущенных событий: %v", err)
+	}
+
+	logrus.Infof("Подписка на Transfer события контракта %s активна", el.contractAddress.Hex())
+	onActive()
+
+	// События придерживаются, пока их блок не наберет confirmations подтверждений, как при опросе.
+	// Отмененные реорганизацией события (Removed) убираются из очереди и в приемник не попадают
+	var pending []types.Log
+	ticker := time.NewTicker(el.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			pending = queueEvent(pending, event.Raw)
+		case <-ticker.C:
+			if pending, err = el.flushConfirmed(ctx, wsClient, pending); err != nil {
+				return err
+			}
+		case err := <-sub.Err():
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// flushConfirmed передает в приемник события, набравшие confirmations подтверждений, и возвращает остальные.
+// При ошибке чекпоинт не сдвигается: после переподключения события перечитает дозагрузка
+func (el *EventListener) flushConfirmed(ctx context.Context, client *ethclient.Client, pending []types.Log) ([]types.Log, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return pending, fmt.Errorf("ошибка получения текущего блока: %v", err)
+	}
+	if header.Number.Uint64() < confirmations {
+		return pending, nil
+	}
+	safe := header.Number.Uint64() - confirmations
+
+	ready, rest := splitConfirmed(pending, safe)
+	if err := el.handleLogs(ctx, ready); err != nil {
+		return pending, fmt.Errorf("ошибка обработки событий из подписки: %v", err)
+	}
+
+	el.setCheckpoint(safe)
+	return rest, nil
+}
+
+// queueEvent ставит событие подписки в очередь ожидания подтверждений.
+// Событие с Removed отменяет ранее полученное событие с тем же ключом
+func queueEvent(pending []types.Log, vLog types.Log) []types.Log {
+	key := eventKey(vLog)
+	if vLog.Removed {
+		kept := pending[:0]
+		for _, queued := range pending {
+			if eventKey(queued) != key {
+				kept = append(kept, queued)
+			}
+		}
+		return kept
+	}
+
+	for _, queued := range pending {
+		if eventKey(queued) == key {
+			return pending
+		}
+	}
+	return append(pending, vLog)
+}
+
+// splitConfirmed делит очередь на события из блоков не выше safe и остальные, сохраняя порядок
+func splitConfirmed(pending []types.Log, safe uint64) (ready, rest []types.Log) {
+	for _, vLog := range pending {
+		if vLog.BlockNumber <= safe {
+			ready = append(ready, vLog)
+		} else {
+			rest = append(rest, vLog)
+		}
+	}
+	return ready, rest
+}
+
+// backfill загружает события с последнего чекпоинта до последнего подтвержденного блока
+func (el *EventListener) backfill(ctx context.Context, client *ethclient.Client) error {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка получения текущего блока: %v", err)
+	}
+	if header.Number.Uint64() < confirmations {
+		return nil
+	}
+	head := header.Number.Uint64() - confirmations
+
+	checkpoint := el.getCheckpoint()
+	if checkpoint == 0 {
+		// Первый запуск без чекпоинта - начинаем с последнего подтвержденного блока
+		el.setCheckpoint(head)
+		return nil
+	}
+
+	// Блок чекпоинта перечитываем целиком: он мог быть обработан не полностью
+	from := checkpoint
+	if from > head {
+		return nil
+	}
+
+	logrus.Infof("Дозагрузка событий с блока #%d по #%d", from, head)
+
+	for start := from; start <= head; start += maxBackfillRange {
+		end := start + maxBackfillRange - 1
+		if end > head {
+			end = head
+		}
+
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{el.contractAddress},
+			Topics:    el.transferTopics(),
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка фильтрации логов: %v", err)
+		}
+
+		if err := el.handleLogs(ctx, logs); err != nil {
+			return err
+		}
+
+		el.setCheckpoint(end)
+	}
+
+	return nil
+}
+
+// transferTopics строит фильтр топиков Transfer с учетом фильтров по отправителю и получателю
+func (el *EventListener) transferTopics() [][]common.Hash {
+	return [][]common.Hash{
+		{el.erc20Contract.GetABI().Events["Transfer"].ID},
+		addressTopics(el.fromFilter),
+		addressTopics(el.toFilter),
+	}
+}
+
+// addressTopics преобразует список адресов в значения индексированного топика
+func addressTopics(addresses []common.Address) []common.Hash {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	topics := make([]common.Hash, len(addresses))
+	for i, address := range addresses {
+		topics[i] = common.BytesToHash(address.Bytes())
+	}
+	return topics
+}
+
+// eventKey уникально идентифицирует событие в цепочке
+func eventKey(vLog types.Log) string {
+	return fmt.Sprintf("%s-%d", vLog.TxHash.Hex(), vLog.Index)
+}
+
+// eventDeduplicator - ограниченный по размеру набор уже переданных событий (FIFO вытеснение)
+type eventDeduplicator struct {
+	mu    sync.Mutex
+	keys  map[string]struct{}
+	order []string
+	limit int
+}
+
+func newEventDeduplicator(limit int) *eventDeduplicator {
+	return &eventDeduplicator{
+		keys:  make(map[string]struct{}, limit),
+		limit: limit,
+	}
+}
+
+func (d *eventDeduplicator) Contains(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, exists := d.keys[key]
+	return exists
+}
+
+func (d *eventDeduplicator) Add(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.keys[key]; exists {
+		return
+	}
+
+	d.keys[key] = struct{}{}
+	d.order = append(d.order, key)
+
+	// Вытесняем самые старые ключи
+	for len(d.order) > d.limit {
+		delete(d.keys, d.order[0])
+		d.order = d.order[1:]
+	}
+}
@@ -0,0 +1,124 @@
+package listeners
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func transferLog(block uint64, tx byte, index uint) types.Log {
+	return types.Log{BlockNumber: block, TxHash: common.Hash{tx}, Index: index}
+}
+
+func TestQueueEventReorg(t *testing.T) {
+	var pending []types.Log
+	pending = queueEvent(pending, transferLog(100, 1, 0))
+	pending = queueEvent(pending, transferLog(100, 1, 1))
+	pending = queueEvent(pending, transferLog(101, 2, 0))
+
+	// Повтор того же события (переподключение, пересечение с дозагрузкой) не дублируется
+	pending = queueEvent(pending, transferLog(100, 1, 0))
+	if len(pending) != 3 {
+		t.Fatalf("в очереди %d событий, ожидалось 3", len(pending))
+	}
+
+	// Реорганизация отменила второе событие блока 100
+	removed := transferLog(100, 1, 1)
+	removed.Removed = true
+	pending = queueEvent(pending, removed)
+	if len(pending) != 2 {
+		t.Fatalf("после Removed в очереди %d событий, ожидалось 2", len(pending))
+	}
+	for _, vLog := range pending {
+		if eventKey(vLog) == eventKey(removed) {
+			t.Fatalf("отмененное событие %s осталось в очереди", eventKey(removed))
+		}
+	}
+
+	// Removed для события, которого нет в очереди, ничего не добавляет
+	unknown := transferLog(90, 9, 0)
+	unknown.Removed = true
+	if pending = queueEvent(pending, unknown); len(pending) != 2 {
+		t.Errorf("Removed неизвестного события изменил очередь: %d событий", len(pending))
+	}
+}
+
+func TestSplitConfirmed(t *testing.T) {
+	pending := []types.Log{transferLog(100, 1, 0), transferLog(105, 2, 0), transferLog(100, 3, 4), transferLog(112, 4, 0)}
+
+	ready, rest := splitConfirmed(pending, 105)
+
+	wantReady := []string{eventKey(pending[0]), eventKey(pending[1]), eventKey(pending[2])}
+	if len(ready) != len(wantReady) {
+		t.Fatalf("готово %d событий, ожидалось %d", len(ready), len(wantReady))
+	}
+	for i, vLog := range ready {
+		if eventKey(vLog) != wantReady[i] {
+			t.Errorf("событие %d: %s, ожидалось %s (порядок должен сохраняться)", i, eventKey(vLog), wantReady[i])
+		}
+	}
+	if len(rest) != 1 || rest[0].BlockNumber != 112 {
+		t.Errorf("в очереди остались %v, ожидался только блок 112", rest)
+	}
+
+	if ready, rest = splitConfirmed(nil, 105); ready != nil || rest != nil {
+		t.Errorf("пустая очередь: ready %v, rest %v", ready, rest)
+	}
+}
+
+func TestEventDeduplicatorEvictsOldest(t *testing.T) {
+	seen := newEventDeduplicator(3)
+	for i := 0; i < 4; i++ {
+		seen.Add(fmt.Sprintf("key-%d", i))
+	}
+	// Повторное добавление не сдвигает порядок вытеснения
+	seen.Add("key-3")
+
+	if seen.Contains("key-0") {
+		t.Error("самый старый ключ не вытеснен")
+	}
+	for i := 1; i < 4; i++ {
+		if key := fmt.Sprintf("key-%d", i); !seen.Contains(key) {
+			t.Errorf("ключ %s вытеснен раньше времени", key)
+		}
+	}
+}
+
+// memoryCheckpoints - хранилище чекпоинтов в памяти
+type memoryCheckpoints struct {
+	blocks map[string]uint64
+	saves  int
+}
+
+func (m *memoryCheckpoints) LoadCheckpoint(contractAddress string) (uint64, error) {
+	return m.blocks[contractAddress], nil
+}
+
+func (m *memoryCheckpoints) SaveCheckpoint(contractAddress string, block uint64) error {
+	m.blocks[contractAddress] = block
+	m.saves++
+	return nil
+}
+
+func TestSetCheckpointPersistsOnlyForward(t *testing.T) {
+	store := &memoryCheckpoints{blocks: make(map[string]uint64)}
+	contract := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	el := &EventListener{contractAddress: contract, checkpoints: store}
+
+	el.setCheckpoint(120)
+	el.setCheckpoint(110) // запоздавшая порция не откатывает чекпоинт
+	el.setCheckpoint(120)
+	el.setCheckpoint(130)
+
+	if got := el.getCheckpoint(); got != 130 {
+		t.Errorf("чекпоинт в памяти %d, ожидался 130", got)
+	}
+	if got := store.blocks[contract.Hex()]; got != 130 {
+		t.Errorf("сохраненный чекпоинт %d, ожидался 130", got)
+	}
+	if store.saves != 2 {
+		t.Errorf("чекпоинт сохранялся %d раз, ожидалось 2 (только при сдвиге вперед)", store.saves)
+	}
+}
@@ -0,0 +1,21 @@
+const { ethers } = require("hardhat");
+
+async function main() {
+
+  console.log("\n🚀 Деплой Multicall3...");
+  const Multicall = await ethers.getContractFactory("Multicall3");
+  const multicall = await Multicall.deploy();
+  await multicall.waitForDeployment();
+
+  const multicallAddress = await multicall.getAddress();
+  console.log("✅ Multicall3 задеплоен:", multicallAddress);
+  console.log("ℹ️  Укажите его в backend/.env: ETH_MULTICALL_ADDRESS=" + multicallAddress);
+
+}
+
+main()
+    .then(() => process.exit(0))
+    .catch((error) => {
+      console.error(error);
+      process.exit(1);
+    });